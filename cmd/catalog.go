@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+	"github.com/spf13/cobra"
+)
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Каталог образов рантаймов",
+	Long:  `Офлайн-каталог тегов официальных образов (golang, node, python, eclipse-temurin) и дат окончания поддержки.`,
+}
+
+var catalogUpdateCmd = &cobra.Command{
+	Use:   "update <file>",
+	Short: "Заменить каталог образов содержимым файла",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := catalog.Import(args[0], catalog.DefaultPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Catalog updated: %s (updated_at %s)\n", catalog.DefaultPath, c.UpdatedAt)
+
+		names := make([]string, 0, len(c.Images))
+		for name := range c.Images {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		now := time.Now()
+		for _, name := range names {
+			img := c.Images[name]
+			supported := 0
+			for _, r := range img.Releases {
+				if !r.EOLAt(now) {
+					supported++
+				}
+			}
+			latest, _ := c.Resolve(name, "")
+			fmt.Printf("  %-8s %s: %d releases, %d supported, default %s\n", name, img.Repository, len(img.Releases), supported, latest.Version)
+		}
+		return nil
+	},
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogUpdateCmd)
+	rootCmd.AddCommand(catalogCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+	"github.com/Dancoi/gogen-self-deploy/internal/dockerlint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFormat string
+	lintOutput string
+	lintFailOn string
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [Dockerfile...]",
+	Short: "Проверить Dockerfile",
+	Long: `Офлайн-линтер Dockerfile (по умолчанию gentmp/Dockerfile): теги latest, запуск от root, apt-get без очистки,
+ADD по URL, пакеты без версий, отсутствие HEALTHCHECK, секреты в ENV/ARG, COPY . . до установки зависимостей.
+Для CI: lint --format codequality -o gl-code-quality-report.json --fail-on major — ненулевой код выхода
+при находках не ниже порога, отчёт подключается через artifacts:reports:codequality.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := dockerlint.ParseFormat(lintFormat)
+		if err != nil {
+			return err
+		}
+		failOn, err := dockerlint.ParseSeverity(lintFailOn)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			args = []string{"gentmp/Dockerfile"}
+		}
+		// без каталога образов линтер работает, только не подсказывает теги
+		cat, _ := catalog.Load()
+		var findings []dockerlint.Finding
+		for _, file := range args {
+			found, err := dockerlint.LintFile(file, cat, dockerlint.App{})
+			if err != nil {
+				return err
+			}
+			findings = append(findings, found...)
+		}
+
+		var buf bytes.Buffer
+		if err := dockerlint.Write(&buf, findings, format); err != nil {
+			return err
+		}
+		if lintOutput == "" {
+			if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		} else {
+			if err := os.WriteFile(lintOutput, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("write lint report: %w", err)
+			}
+			fmt.Fprintln(os.Stderr, "Saved to:", lintOutput)
+		}
+		if failing := dockerlint.Failing(findings, failOn); len(failing) > 0 {
+			return fmt.Errorf("%d Dockerfile issue(s) at or above %s", len(failing), failOn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", string(dockerlint.FormatText), "формат отчёта: text|json|codequality")
+	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", "", "файл для отчёта (по умолчанию stdout)")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", string(dockerlint.SeverityMajor), "порог для ненулевого кода выхода: info|minor|major|critical|blocker|none")
+	rootCmd.AddCommand(lintCmd)
+}
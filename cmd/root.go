@@ -9,14 +9,29 @@ import (
 	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
 	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/fetcher"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/compose_generators"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/k8s_generators"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/pipelines_generators"
+	"github.com/Dancoi/gogen-self-deploy/internal/report"
 	"github.com/spf13/cobra"
 )
 
+var (
+	reportFormat string
+	reportOutput string
+	k8sMode      string
+	deployTarget string
+	reviewApps   bool
+	hardened     string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "gogen-self-deploy",
 	Short: "Самостоятельный деплой",
 	Long:  `gogen-self-deploy - это инструмент для самостоятельного деплоя приложений.`,
+	// <repo-url> <dir> — не подкоманды
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 2 {
 			_ = cmd.Help()
@@ -25,6 +40,36 @@ var rootCmd = &cobra.Command{
 		repoURL := args[0]
 		dir := args[1]
 
+		format, err := report.ParseFormat(reportFormat)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+		mode, err := k8s_generators.ParseMode(k8sMode)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+		hardening, err := dockerfiles_generators.ParseHardening(hardened)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+		strategy, err := pipelines_generators.ParseDeployStrategy(deployTarget, mode)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+		// helm выкатывает чарты, Argo CD — Helm/Kustomize-приложения: нужная конфигурация генерируется сама
+		mode = strategy.K8sMode(mode)
+		// Без --output отчёт — единственное, что пишется в stdout: прогресс клонирования
+		// и вывод генераторов уходят в stderr, чтобы stdout можно было сразу отдать jq/yq
+		reportOut := os.Stdout
+		if reportOutput == "" {
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = reportOut }()
+		}
+
 		DTO_Repo := dto.RepoDTO{
 			RepoURL:   repoURL,
 			OutputDir: dir,
@@ -39,7 +84,7 @@ var rootCmd = &cobra.Command{
 		fmt.Println("Repository cloned successfully to", DTO_Repo.OutputDir)
 
 		var analyzerRep *analyzer.ProjectAnalysisResult
-		analyzerRep, err := analyzer.AnalyzRepo(DTO_Repo)
+		analyzerRep, err = analyzer.AnalyzRepo(DTO_Repo)
 		if err != nil {
 			fmt.Println("Error analyzing repository:", err)
 			return
@@ -47,39 +92,102 @@ var rootCmd = &cobra.Command{
 		fmt.Println("Repository analyzed successfully")
 
 		if analyzerRep != nil {
-			analyzerRep.PrintSummary()
+			if reportOutput != "" {
+				if err := report.WriteFile(reportOutput, analyzerRep, format, repoRoot); err != nil {
+					fmt.Println("Error writing analysis report:", err)
+				} else {
+					fmt.Println("Analysis report saved to:", reportOutput)
+				}
+			} else if err := report.Write(reportOut, analyzerRep, format, repoRoot); err != nil {
+				fmt.Println("Error writing analysis report:", err)
+			}
+			for _, w := range analyzerRep.Warnings {
+				fmt.Println("Warning:", w)
+			}
 		}
 
-		// Языковой выбор: java -> node -> python -> go
+		// Генераторы читают только контракт dto: анализатор и генераторы развиваются независимо
+		project := analyzerRep.ToDTO(repoRoot)
+		project.Options.Hardening = hardening
+
+		// Языковой выбор: java -> node -> python -> go -> php -> ruby
+		var pipelineLang string
 		switch {
 		case hasLanguage(analyzerRep, analyzer.LanguageJava):
-			if err := pipelines_generators.GenerateJavaPipeline(DTO_Repo.RepoName, repoRoot, analyzerRep); err != nil {
+			pipelineLang = dto.LangJava
+			if err := pipelines_generators.GenerateJavaPipeline(DTO_Repo.RepoName, repoRoot, project); err != nil {
 				fmt.Println("Error generating Java pipeline:", err)
 			} else {
 				fmt.Println("Java pipeline generated and printed")
 			}
 		case hasLanguage(analyzerRep, analyzer.LanguageJavaScript), hasLanguage(analyzerRep, analyzer.LanguageTypeScript):
-			if err := pipelines_generators.GenerateNodePipeline(DTO_Repo.RepoName, repoRoot, analyzerRep); err != nil {
+			pipelineLang = dto.LangNode
+			if err := pipelines_generators.GenerateNodePipeline(DTO_Repo.RepoName, repoRoot, project); err != nil {
 				fmt.Println("Error generating Node pipeline:", err)
 			} else {
 				fmt.Println("Node pipeline generated and printed")
 			}
 		case hasLanguage(analyzerRep, analyzer.LanguagePython):
-			if err := pipelines_generators.GeneratePythonPipeline(analyzerRep); err != nil {
+			pipelineLang = dto.LangPython
+			if err := pipelines_generators.GeneratePythonPipeline(project); err != nil {
 				fmt.Println("Error generating Python pipeline:", err)
 			} else {
 				fmt.Println("Python pipeline generated and printed")
 			}
 		case hasLanguage(analyzerRep, analyzer.LanguageGo):
-			if err := pipelines_generators.GenerateGoPipeline(DTO_Repo.RepoName, repoRoot, analyzerRep); err != nil {
+			pipelineLang = dto.LangGo
+			if err := pipelines_generators.GenerateGoPipeline(DTO_Repo.RepoName, repoRoot, project); err != nil {
 				fmt.Println("Error generating Go pipeline:", err)
 			} else {
 				fmt.Println("Go pipeline generated and printed")
 			}
+		case hasLanguage(analyzerRep, analyzer.LanguagePHP):
+			pipelineLang = dto.LangPHP
+			if err := pipelines_generators.GeneratePHPPipeline(DTO_Repo.RepoName, repoRoot, project); err != nil {
+				fmt.Println("Error generating PHP pipeline:", err)
+			} else {
+				fmt.Println("PHP pipeline generated and printed")
+			}
+		case hasLanguage(analyzerRep, analyzer.LanguageRuby):
+			pipelineLang = dto.LangRuby
+			if err := pipelines_generators.GenerateRubyPipeline(DTO_Repo.RepoName, repoRoot, project); err != nil {
+				fmt.Println("Error generating Ruby pipeline:", err)
+			} else {
+				fmt.Println("Ruby pipeline generated and printed")
+			}
 		default:
 			fmt.Println("No supported languages detected for pipeline generation")
 		}
 
+		// docker-compose: приложение + найденные базы/кеши/брокеры; конфигурация из инвентаря env
+		if pipelineLang != "" {
+			if _, err := dockerfiles_generators.LintDockerfile(repoRoot, pipelineLang, project); err != nil {
+				fmt.Println("Error linting Dockerfile:", err)
+			}
+			if _, err := dockerfiles_generators.GenerateDockerignore(repoRoot, pipelineLang, project); err != nil {
+				fmt.Println("Error generating Dockerfile.dockerignore:", err)
+			}
+			deploy := pipelines_generators.DeployOptions{Strategy: strategy, K8sMode: mode, ReviewApps: reviewApps}
+			if m := project.ModuleFor(pipelineLang); m != nil {
+				deploy.Module = m.Name
+			}
+			if err := pipelines_generators.GenerateDeployJobs(deploy, DTO_Repo.RepoName, project); err != nil {
+				fmt.Println("Error generating deploy jobs:", err)
+			}
+			if _, err := compose_generators.GenerateCompose(DTO_Repo.RepoName, pipelineLang, project); err != nil {
+				fmt.Println("Error generating docker-compose:", err)
+			}
+			if _, err := compose_generators.GenerateComposeDev(DTO_Repo.RepoName, repoRoot, pipelineLang, project); err != nil {
+				fmt.Println("Error generating docker-compose.dev.yml:", err)
+			}
+			if _, err := compose_generators.GenerateEnvExample(pipelineLang, project); err != nil {
+				fmt.Println("Error generating .env.example:", err)
+			}
+			if _, err := k8s_generators.Generate(mode, DTO_Repo.RepoName, repoRoot, project); err != nil {
+				fmt.Println("Error generating k8s config:", err)
+			}
+		}
+
 		time.Sleep(2 * time.Second)
 		if err := fetcher.DeleteRepo(DTO_Repo.RepoURL, DTO_Repo.OutputDir); err != nil {
 			fmt.Println("Error deleting repository:", err)
@@ -115,6 +223,14 @@ func hasLanguage(analysis *analyzer.ProjectAnalysisResult, lang analyzer.Languag
 		if g, ok := analysis.Languages["Go"]; ok && g > 0 {
 			return true
 		}
+	case analyzer.LanguagePHP:
+		if p, ok := analysis.Languages["PHP"]; ok && p > 0 {
+			return true
+		}
+	case analyzer.LanguageRuby:
+		if r, ok := analysis.Languages["Ruby"]; ok && r > 0 {
+			return true
+		}
 	}
 	return false
 }
@@ -128,4 +244,11 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.Flags().StringVarP(&reportFormat, "format", "f", string(report.FormatJSON), "формат отчёта анализа: json|yaml|markdown|table")
+	rootCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "файл для отчёта анализа (по умолчанию stdout)")
+	rootCmd.Flags().StringVar(&k8sMode, "k8s", string(k8s_generators.ModeManifests), "формат k8s-конфигурации: manifests|helm|kustomize")
+	rootCmd.Flags().StringVar(&deployTarget, "deploy", "", "стратегия деплоя: kubectl|helm|compose|argocd (по умолчанию — по --k8s)")
+	rootCmd.Flags().BoolVar(&reviewApps, "review-apps", false, "review app на каждый merge request (окружение review/<ветка> с on_stop и auto_stop_in)")
+	rootCmd.Flags().StringVar(&hardened, "hardened", "", "усиленный профиль: непривилегированный UID, HEALTHCHECK, read-only ФС в compose/k8s; =distroless|scratch — рантайм без shell")
+	rootCmd.Flags().Lookup("hardened").NoOptDefVal = "true"
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var schemaOutput string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "JSON Schema отчёта анализа",
+	Long:  `Выводит JSON Schema результата анализа (--format json/yaml) текущей версии schema_version.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		raw, err := json.MarshalIndent(report.Schema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal schema: %w", err)
+		}
+		raw = append(raw, '\n')
+		if schemaOutput == "" {
+			_, err = os.Stdout.Write(raw)
+			return err
+		}
+		if err := os.WriteFile(schemaOutput, raw, 0o644); err != nil {
+			return fmt.Errorf("write schema: %w", err)
+		}
+		fmt.Println("Saved to:", schemaOutput)
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "файл для схемы (по умолчанию stdout)")
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -12,10 +12,13 @@ func AnalyzRepo(dto dto.RepoDTO) (result *ProjectAnalysisResult, err error) {
 
 	// Инициализация
 	result = &ProjectAnalysisResult{
-		RepositoryName: dto.RepoName,
-		Modules:        []*ProjectModule{},
-		Languages:      make(map[string]float64),
-		Infrastructure: []string{},
+		SchemaVersion:   AnalysisSchemaVersion,
+		RepositoryName:  dto.RepoName,
+		Modules:         []*ProjectModule{},
+		Languages:       make(map[string]float64),
+		Infrastructure:  []string{},
+		BackingServices: []BackingService{},
+		EnvVars:         []EnvVar{},
 	}
 
 	// 1. Глобальный анализ (Языки + Инфраструктура)
@@ -26,6 +29,29 @@ func AnalyzRepo(dto dto.RepoDTO) (result *ProjectAnalysisResult, err error) {
 	AnalyzeJavaModule(result, root) // Внутри теперь есть фильтр от "мусорных" модулей
 	AnalyzeNodeModule(result, root)
 	AnalyzePythonModule(result, root)
+	AnalyzePHPModule(result, root)
+	AnalyzeRubyModule(result, root)
+
+	// 2.0.1 Dockerfile'ы репозитория и модули, которые они собирают
+	DiscoverDockerfiles(result, root)
+
+	// 2.1 Порты приложений по уликам из кода и конфигов
+	InferModulePorts(result, root)
+
+	// 2.1.1 Команды запуска Python-приложений (порт уже известен)
+	ResolvePythonEntrypoints(result)
+
+	// 2.1.2 Версии рантаймов по каталогу образов, предупреждения об EOL
+	PinRuntimeVersions(result)
+
+	// 2.1.3 Эндпоинты проверки здоровья для HEALTHCHECK и проб k8s
+	DetectHealthEndpoints(result)
+
+	// 2.2 Базы, кеши и брокеры по клиентским библиотекам
+	DetectBackingServices(result)
+
+	// 2.3 Переменные окружения, которые читает код
+	CollectEnvVars(result, root)
 
 	// 3. Стратегия
 	if len(result.Modules) > 1 {
@@ -0,0 +1,234 @@
+package analyzer
+
+import (
+	"cmp"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// detectedFileNames — файлы, наличие которых в каталоге модуля попадает в AnalyzeDTO.DetectedFiles.
+var detectedFileNames = []string{
+	"go.mod", "go.sum", "pom.xml", "build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradlew",
+	"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "tsconfig.json",
+	"pyproject.toml", "poetry.lock", "requirements.txt", "Pipfile", "Pipfile.lock", "setup.py", "manage.py", "Procfile",
+	"composer.json", "composer.lock", "Gemfile", "Gemfile.lock",
+	"Dockerfile", ".dockerignore", "docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml",
+}
+
+var composeFileNames = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// pythonLinters — линтеры из зависимостей для PythonMeta.LintCommand, в порядке предпочтения.
+var pythonLinters = []struct{ pkg, cmd string }{
+	{"ruff", "ruff check ."},
+	{"flake8", "flake8"},
+	{"pylint", "pylint ."},
+}
+
+// ToDTO переводит результат анализа в контракт генераторов (internal/dto): по AnalyzeDTO на модуль
+// с языковыми деталями (NodeMeta, PythonMeta) и сведениями о контейнеризации.
+// repoRoot — корень клона, относительно него ищутся compose-файлы.
+func (par *ProjectAnalysisResult) ToDTO(repoRoot string) *dto.ProjectDTO {
+	project := &dto.ProjectDTO{
+		RepoName:       par.RepositoryName,
+		Strategy:       string(par.PipelineStrategy),
+		Modules:        make([]*dto.AnalyzeDTO, 0, len(par.Modules)),
+		EnvVars:        make([]dto.EnvVar, 0, len(par.EnvVars)),
+		Infrastructure: par.Infrastructure,
+		KustomizeFiles: par.KustomizeFiles,
+	}
+	for _, m := range par.Modules {
+		project.Modules = append(project.Modules, m.toDTO(repoRoot))
+	}
+	for _, g := range par.BuildGraphs {
+		graph := dto.BuildGraph{BuildTool: string(g.BuildTool), Root: g.Root, Orchestrator: g.Orchestrator}
+		for _, n := range g.Nodes {
+			graph.Nodes = append(graph.Nodes, dto.BuildGraphNode{
+				Name: n.Name, Path: n.Path, Packaging: n.Packaging, Framework: n.Framework,
+				Runnable: n.Runnable, DependsOn: n.DependsOn, Scripts: n.Scripts,
+			})
+		}
+		project.BuildGraphs = append(project.BuildGraphs, graph)
+	}
+	for _, v := range par.EnvVars {
+		project.EnvVars = append(project.EnvVars, dto.EnvVar{
+			Name: v.Name, Default: v.Default, HasDefault: v.HasDefault, Required: v.Required, Secret: v.Secret, Modules: v.Modules, Sources: v.Sources,
+		})
+	}
+	return project
+}
+
+func (m *ProjectModule) toDTO(repoRoot string) *dto.AnalyzeDTO {
+	dir := filepath.Dir(m.ModulePath)
+	out := &dto.AnalyzeDTO{
+		Name:             m.Name,
+		ModulePath:       m.ModulePath,
+		Language:         dtoLanguage(m.Language),
+		Framework:        m.Framework,
+		FrameworkVersion: m.FrameworkVersion,
+		BuildTool:        string(m.BuildTool),
+		LanguageVersion:  m.LanguageVersion,
+		Dependencies:     m.Dependencies,
+		Services:         m.Services,
+		BuildCommand:     m.BuildCommand,
+		TestCommand:      m.TestCommand,
+		StartCommand:     m.StartCommand,
+		ArtifactPath:     m.ArtifactPath,
+		BuilderImage:     m.BuilderImage,
+		RuntimeImage:     m.RuntimeImage,
+		BuildRoot:        m.BuildRoot,
+		ReactorModule:    m.ReactorModule,
+		BuildTask:        m.BuildTask,
+	}
+	out.AppPort, _ = strconv.Atoi(strings.TrimSpace(m.AppPort))
+	out.HealthPath = m.HealthPath
+	for _, t := range m.BuildTargets {
+		out.BuildTargets = append(out.BuildTargets, dto.BuildTarget{Name: t.Name, Path: t.Path})
+	}
+	for _, name := range detectedFileNames {
+		if fileExists(filepath.Join(dir, name)) {
+			out.DetectedFiles = append(out.DetectedFiles, name)
+		}
+	}
+
+	out.Docker = m.dockerMeta(dir, repoRoot)
+	switch m.Language {
+	case LanguageJavaScript, LanguageTypeScript:
+		out.Node = m.nodeMeta(dir)
+	case LanguagePython:
+		out.Python = m.pythonMeta(dir)
+	}
+	return out
+}
+
+func dtoLanguage(lang Language) string {
+	switch lang {
+	case LanguageJavaScript:
+		return dto.LangNode
+	case LanguageKotlin:
+		// Kotlin собирается Gradle/Maven так же, как Java
+		return dto.LangJava
+	}
+	return string(lang)
+}
+
+func (m *ProjectModule) dockerMeta(dir, repoRoot string) dto.DockerMeta {
+	meta := dto.DockerMeta{ImageName: imageName(m.Name)}
+	if m.DockerfilePath != "" {
+		// пути от корня репозитория: пайплайн и compose собирают Dockerfile на месте
+		meta.DockerfilePath = relDir(repoRoot, m.DockerfilePath)
+		meta.DockerContext = relDir(repoRoot, cmp.Or(m.DockerContext, filepath.Dir(m.DockerfilePath)))
+	}
+	meta.DockerfileDetected = meta.DockerfilePath != ""
+	for _, d := range []string{dir, repoRoot} {
+		for _, name := range composeFileNames {
+			if d != "" && fileExists(filepath.Join(d, name)) {
+				meta.ComposeDetected = true
+			}
+		}
+	}
+	for _, e := range m.PortEvidence {
+		if e.Reason == "Dockerfile EXPOSE" {
+			meta.ExposedPort, _ = strconv.Atoi(e.Port)
+			break
+		}
+	}
+	image := m.RuntimeImage
+	if image == "" {
+		image = m.BuilderImage
+	}
+	switch {
+	case strings.Contains(image, "distroless"):
+		meta.PreferredBase = dto.BaseDistroless
+	case strings.Contains(image, "slim"):
+		meta.PreferredBase = dto.BaseSlim
+	case strings.Contains(image, "alpine"):
+		meta.PreferredBase = dto.BaseAlpine
+	}
+	return meta
+}
+
+// imageName — имя образа из имени модуля: последний сегмент пути Go-модуля, без scope npm.
+func imageName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = name[strings.LastIndex(name, "/")+1:]
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	if out := strings.Trim(b.String(), "-._"); out != "" {
+		return out
+	}
+	return "app"
+}
+
+func (m *ProjectModule) nodeMeta(dir string) *dto.NodeMeta {
+	meta := &dto.NodeMeta{
+		PackageManager: string(m.BuildTool),
+		NodeVersion:    m.LanguageVersion,
+		HasTsconfig:    fileExists(filepath.Join(dir, "tsconfig.json")),
+		Framework:      frameworkSlug(m.Framework),
+		BuildDir:       m.ArtifactPath,
+		StartCommand:   m.StartCommand,
+		StaticSite:     m.StaticSite,
+	}
+	var pkg packageJSON
+	if content, err := os.ReadFile(m.ModulePath); err == nil && json.Unmarshal(content, &pkg) == nil {
+		_, meta.BuildScript = pkg.Scripts["build"]
+		_, meta.TestScript = pkg.Scripts["test"]
+		_, meta.LintScript = pkg.Scripts["lint"]
+	}
+	// lockfile лежит в корне workspace-а, а не в каталоге пакета
+	lockDir := dir
+	if m.ReactorModule != "" {
+		lockDir = strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(dir), m.ReactorModule), "/")
+	}
+	for _, name := range []string{"pnpm-lock.yaml", "yarn.lock", "package-lock.json"} {
+		if fileExists(filepath.Join(filepath.FromSlash(lockDir), name)) {
+			meta.Lockfile = name
+			break
+		}
+	}
+	return meta
+}
+
+func (m *ProjectModule) pythonMeta(dir string) *dto.PythonMeta {
+	meta := &dto.PythonMeta{
+		DependencyManager: string(m.BuildTool),
+		RuntimeVersion:    m.LanguageVersion,
+		HasPyproject:      fileExists(filepath.Join(dir, "pyproject.toml")),
+		HasRequirements:   fileExists(filepath.Join(dir, "requirements.txt")),
+		HasPipfile:        fileExists(filepath.Join(dir, "Pipfile")),
+		Framework:         frameworkSlug(m.Framework),
+		TestCommand:       m.TestCommand,
+		StartCommand:      m.StartCommand,
+		WsgiModule:        m.WSGIModule,
+		AsgiModule:        m.ASGIModule,
+	}
+	for _, l := range pythonLinters {
+		if containsString(m.Dependencies, l.pkg) {
+			meta.LintCommand = l.cmd
+			break
+		}
+	}
+	return meta
+}
+
+// frameworkSlug: "Next.js" -> "nextjs", "FastAPI" -> "fastapi".
+func frameworkSlug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,314 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dockerConfigDirs — каталоги, где Dockerfile'ы лежат отдельно от кода (build/, docker/, deploy/ ...).
+// Если копируемые пути не подсказывают контекст, им считается родитель такого каталога.
+var dockerConfigDirs = map[string]bool{
+	"build": true, "docker": true, ".docker": true, "dockerfiles": true, "containers": true,
+	"deploy": true, "deployment": true, "deployments": true, "ci": true, ".ci": true,
+}
+
+// dockerfileDevVariants — варианты Dockerfile для локальной разработки и тестов: образ для реестра из них не собирается.
+var dockerfileDevVariants = map[string]bool{
+	"dev": true, "develop": true, "development": true, "local": true, "debug": true,
+	"test": true, "tests": true, "e2e": true, "ci": true,
+}
+
+var dockerfileProdVariants = map[string]bool{"prod": true, "production": true, "release": true}
+
+// dockerfileNonSourceExts — не Dockerfile'ы, хоть и названы похоже: шаблоны, примеры, ignore-файлы.
+var dockerfileNonSourceExts = map[string]bool{
+	".dockerignore": true, ".tmpl": true, ".tpl": true, ".template": true, ".j2": true, ".jinja": true,
+	".in": true, ".example": true, ".sample": true, ".bak": true, ".orig": true, ".md": true,
+}
+
+// dockerfileCandidate — найденный Dockerfile, его контекст сборки и пути, которые он копирует.
+type dockerfileCandidate struct {
+	path    string
+	context string
+	variant string   // "prod" для Dockerfile.prod, "api" для api.Dockerfile, "" для Dockerfile
+	sources []string // существующие пути из COPY/ADD, абсолютные
+}
+
+// DiscoverDockerfiles находит Dockerfile'ы репозитория — Dockerfile, Dockerfile.<вариант>, <имя>.Dockerfile,
+// Containerfile, в каталогах модулей и в build/, docker/, deploy/ — и привязывает каждый к модулю,
+// чьи файлы он копирует (COPY/ADD). Контекст сборки — ближайший к Dockerfile каталог, где находятся
+// копируемые пути. Модулю достаётся один Dockerfile: сначала Dockerfile, затем prod-варианты, затем прочие;
+// dev/test-варианты пропускаются.
+func DiscoverDockerfiles(result *ProjectAnalysisResult, root string) {
+	if len(result.Modules) == 0 {
+		return
+	}
+	root = filepath.Clean(root)
+	var found []dockerfileCandidate
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "gentmp" || name == "testdata" || (name != "build" && shouldSkipDir(name))) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		variant, ok := dockerfileVariant(d.Name())
+		if !ok || dockerfileDevVariants[variant] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		found = append(found, newDockerfileCandidate(root, path, variant, dockerCopySources(content)))
+		return nil
+	})
+
+	best := make(map[*ProjectModule]dockerfileCandidate)
+	for _, c := range found {
+		m := c.module(result.Modules)
+		if m == nil {
+			continue
+		}
+		if prev, ok := best[m]; !ok || c.betterFor(m, prev) {
+			best[m] = c
+		}
+	}
+	for m, c := range best {
+		m.DockerfilePath = c.path
+		m.DockerContext = c.context
+	}
+}
+
+// dockerfileVariant: "Dockerfile" -> "", "Dockerfile.prod" -> "prod", "api.Dockerfile" -> "api".
+func dockerfileVariant(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	if lower == "dockerfile" || lower == "containerfile" {
+		return "", true
+	}
+	if dockerfileNonSourceExts[filepath.Ext(lower)] {
+		return "", false
+	}
+	var variant string
+	switch {
+	case strings.HasPrefix(lower, "dockerfile."):
+		variant = strings.TrimPrefix(lower, "dockerfile.")
+	case strings.HasPrefix(lower, "dockerfile-"), strings.HasPrefix(lower, "dockerfile_"):
+		variant = lower[len("dockerfile-"):]
+	case strings.HasSuffix(lower, ".dockerfile"):
+		variant = strings.TrimSuffix(lower, ".dockerfile")
+	default:
+		return "", false
+	}
+	return variant, variant != ""
+}
+
+// dockerCopySources — пути контекста из COPY/ADD: без --from, URL, heredoc и переменных.
+func dockerCopySources(content []byte) []string {
+	var out []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var logical strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			logical.WriteString(strings.TrimSuffix(line, "\\") + " ")
+			continue
+		}
+		logical.WriteString(line)
+		out = append(out, copyInstructionSources(logical.String())...)
+		logical.Reset()
+	}
+	return out
+}
+
+func copyInstructionSources(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return nil
+	}
+	if op := strings.ToUpper(fields[0]); op != "COPY" && op != "ADD" {
+		return nil
+	}
+	args := fields[1:]
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		if strings.HasPrefix(args[0], "--from") {
+			return nil
+		}
+		args = args[1:]
+	}
+	if len(args) > 0 && strings.HasPrefix(args[0], "[") {
+		args = strings.Split(strings.Trim(strings.Join(args, " "), "[]"), ",")
+		for i := range args {
+			args[i] = strings.Trim(strings.TrimSpace(args[i]), `"`)
+		}
+	}
+	if len(args) < 2 {
+		return nil
+	}
+	var out []string
+	for _, src := range args[:len(args)-1] {
+		if strings.HasPrefix(src, "<<") || strings.Contains(src, "://") || strings.Contains(src, "$") {
+			continue
+		}
+		out = append(out, src)
+	}
+	return out
+}
+
+// newDockerfileCandidate выбирает контекст сборки: из каталога Dockerfile и его предков до корня
+// репозитория — ближайший, где находится больше всего копируемых путей. "COPY . ." контекст
+// не различает: тогда Dockerfile из build/, docker/, deploy/ собирается из родителя этого каталога,
+// остальные — из своего каталога.
+func newDockerfileCandidate(root, path, variant string, sources []string) dockerfileCandidate {
+	c := dockerfileCandidate{path: path, variant: variant}
+	dir := filepath.Dir(path)
+	for d := dir; ; d = filepath.Dir(d) {
+		if matched := resolveCopySources(d, sources, false); len(matched) > len(c.sources) {
+			c.context, c.sources = d, matched
+		}
+		if d == root || d == filepath.Dir(d) {
+			break
+		}
+	}
+	if c.context == "" {
+		c.context = defaultDockerContext(root, dir)
+	}
+	c.sources = resolveCopySources(c.context, sources, true)
+	return c
+}
+
+// resolveCopySources — существующие пути контекста ctx, которые копирует Dockerfile (с раскрытием glob).
+// withContext — учитывать "." (весь контекст).
+func resolveCopySources(ctx string, sources []string, withContext bool) []string {
+	var out []string
+	for _, src := range sources {
+		p := filepath.Join(ctx, src)
+		if p != ctx && !strings.HasPrefix(p, ctx+string(filepath.Separator)) {
+			continue
+		}
+		if p == ctx {
+			if withContext {
+				out = append(out, p)
+			}
+			continue
+		}
+		if strings.ContainsAny(src, "*?[") {
+			matches, _ := filepath.Glob(p)
+			out = append(out, matches...)
+		} else if _, err := os.Stat(p); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func defaultDockerContext(root, dir string) string {
+	rel := relDir(root, dir)
+	if rel == "." {
+		return dir
+	}
+	segments := strings.Split(rel, "/")
+	for i, seg := range segments {
+		if dockerConfigDirs[strings.ToLower(seg)] {
+			return filepath.Join(append([]string{root}, segments[:i]...)...)
+		}
+	}
+	return dir
+}
+
+// module — модуль, чьи файлы копирует Dockerfile: голос за каждый копируемый путь получает модуль
+// с самым глубоким каталогом, содержащим этот путь. Без голосов — модуль, в каталоге которого лежит
+// Dockerfile, модуль с именем варианта или каталога Dockerfile (docker/api/Dockerfile, api.Dockerfile)
+// или единственный модуль внутри контекста.
+func (c dockerfileCandidate) module(modules []*ProjectModule) *ProjectModule {
+	votes := make(map[*ProjectModule]int)
+	for _, p := range c.sources {
+		if m := deepestModule(modules, p); m != nil {
+			votes[m]++
+		}
+	}
+	var winner *ProjectModule
+	for _, m := range modules {
+		if votes[m] > votes[winner] {
+			winner = m
+		}
+	}
+	if winner != nil {
+		return winner
+	}
+	if m := deepestModule(modules, filepath.Dir(c.path)); m != nil {
+		return m
+	}
+	hints := []string{c.variant, strings.ToLower(filepath.Base(filepath.Dir(c.path)))}
+	var inContext []*ProjectModule
+	for _, m := range modules {
+		dir := filepath.Dir(m.ModulePath)
+		for _, h := range hints {
+			if h != "" && (strings.ToLower(filepath.Base(dir)) == h || imageName(m.Name) == h) {
+				return m
+			}
+		}
+		if isWithin(c.context, dir) {
+			inContext = append(inContext, m)
+		}
+	}
+	if len(inContext) == 1 {
+		return inContext[0]
+	}
+	return nil
+}
+
+// betterFor: Dockerfile лучше prod-варианта, тот — прочих; при равенстве — лежащий в каталоге модуля, затем менее глубокий.
+func (c dockerfileCandidate) betterFor(m *ProjectModule, prev dockerfileCandidate) bool {
+	if c.rank() != prev.rank() {
+		return c.rank() < prev.rank()
+	}
+	dir := filepath.Dir(m.ModulePath)
+	if own, prevOwn := filepath.Dir(c.path) == dir, filepath.Dir(prev.path) == dir; own != prevOwn {
+		return own
+	}
+	depth, prevDepth := strings.Count(c.path, string(filepath.Separator)), strings.Count(prev.path, string(filepath.Separator))
+	if depth != prevDepth {
+		return depth < prevDepth
+	}
+	return c.path < prev.path
+}
+
+func (c dockerfileCandidate) rank() int {
+	switch {
+	case c.variant == "":
+		return 0
+	case dockerfileProdVariants[c.variant]:
+		return 1
+	}
+	return 2
+}
+
+// deepestModule — модуль с самым глубоким каталогом, содержащим path.
+func deepestModule(modules []*ProjectModule, path string) *ProjectModule {
+	var best *ProjectModule
+	bestLen := -1
+	for _, m := range modules {
+		dir := filepath.Dir(m.ModulePath)
+		if isWithin(dir, path) && len(dir) > bestLen {
+			best, bestLen = m, len(dir)
+		}
+	}
+	return best
+}
+
+// isWithin: path — это dir или лежит внутри dir.
+func isWithin(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
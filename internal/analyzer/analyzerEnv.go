@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxEnvSources — сколько мест использования храним на одну переменную
+const maxEnvSources = 5
+
+var (
+	envNameRe = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+	jsEnvRe        = regexp.MustCompile(`process\.env\.([A-Z_][A-Z0-9_]*)(?:\s*(?:\|\||\?\?)\s*(?:['"` + "`" + `]([^'"` + "`" + `]*)['"` + "`" + `]|(\d+)))?`)
+	jsEnvIndexRe   = regexp.MustCompile(`process\.env\[\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*\]`)
+	pyEnvIndexRe   = regexp.MustCompile(`os\.environ\[\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*\]`)
+	pyEnvGetRe     = regexp.MustCompile(`os\.(?:environ\.get|getenv)\(\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*(?:,\s*(?:['"]([^'"]*)['"]|(\d+)))?`)
+	springEnvRe    = regexp.MustCompile(`\$\{([A-Z][A-Z0-9_]*)(?::([^}]*))?\}`)
+	phpEnvRe       = regexp.MustCompile(`\b(?:getenv|env)\(\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*(?:,\s*(?:['"]([^'"]*)['"]|(\d+)))?`)
+	rubyEnvIndexRe = regexp.MustCompile(`ENV\[\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*\]`)
+	rubyEnvFetchRe = regexp.MustCompile(`ENV\.fetch\(\s*['"]([A-Z_][A-Z0-9_]*)['"]\s*(?:,\s*(?:['"]([^'"]*)['"]|(\d+)))?`)
+	dotenvLineRe   = regexp.MustCompile(`^\s*(?:export\s+)?([A-Z_][A-Z0-9_]*)\s*=\s*(.*)$`)
+)
+
+// systemEnvVars — переменные окружения среды, а не конфигурация приложения
+var systemEnvVars = map[string]bool{
+	"PATH": true, "HOME": true, "USER": true, "PWD": true, "SHELL": true, "TMPDIR": true,
+	"HOSTNAME": true, "TERM": true, "LANG": true, "CI": true, "GOPATH": true, "GOROOT": true,
+}
+
+// secretMarkers — подстроки имени, по которым переменная считается секретом
+var secretMarkers = []string{"SECRET", "PASSWORD", "PASSWD", "TOKEN", "API_KEY", "PRIVATE_KEY", "ACCESS_KEY", "CREDENTIAL", "DSN", "DATABASE_URL"}
+
+// envRule — регулярка с номерами групп имени и значения по умолчанию (строка / число)
+type envRule struct {
+	re                      *regexp.Regexp
+	nameGroup, defaultGroup int
+	numberGroup             int
+}
+
+// CollectEnvVars собирает инвентарь переменных окружения по всем модулям:
+// os.Getenv/os.LookupEnv, process.env.X, os.environ/os.getenv, Spring ${...}, getenv/env() в PHP,
+// ENV[...] в Ruby и .env.example. Заполняет ProjectAnalysisResult.EnvVars.
+func CollectEnvVars(result *ProjectAnalysisResult, root string) {
+	inventory := make(map[string]*EnvVar)
+	// os.LookupEnv — идиома необязательной настройки: отсутствие переменной приложение обрабатывает само
+	optional := make(map[string]bool)
+	markOptional := func(name string) { optional[name] = true }
+	add := func(module, name, def string, hasDef bool, source string) {
+		if !envNameRe.MatchString(name) || systemEnvVars[name] {
+			return
+		}
+		v, ok := inventory[name]
+		if !ok {
+			v = &EnvVar{Name: name, Secret: isSecretName(name)}
+			inventory[name] = v
+		}
+		if hasDef && !v.HasDefault {
+			v.Default = def
+			v.HasDefault = true
+		}
+		if module != "" && !containsString(v.Modules, module) {
+			v.Modules = append(v.Modules, module)
+		}
+		if len(v.Sources) < maxEnvSources && !containsString(v.Sources, source) {
+			v.Sources = append(v.Sources, source)
+		}
+	}
+
+	for _, m := range result.Modules {
+		dir := filepath.Dir(m.ModulePath)
+		switch m.Language {
+		case LanguageGo:
+			scanGoEnv(dir, root, m.Name, add, markOptional)
+		case LanguageJavaScript, LanguageTypeScript:
+			scanEnvRules(dir, root, m.Name, []string{".js", ".mjs", ".cjs", ".ts", ".tsx", ".jsx"}, []envRule{
+				{re: jsEnvRe, nameGroup: 1, defaultGroup: 2, numberGroup: 3},
+				{re: jsEnvIndexRe, nameGroup: 1},
+			}, add)
+		case LanguagePython:
+			scanEnvRules(dir, root, m.Name, []string{".py"}, []envRule{
+				{re: pyEnvIndexRe, nameGroup: 1},
+				{re: pyEnvGetRe, nameGroup: 1, defaultGroup: 2, numberGroup: 3},
+			}, add)
+		case LanguageJava:
+			scanEnvRules(filepath.Join(dir, "src", "main", "resources"), root, m.Name, []string{".properties", ".yml", ".yaml"}, []envRule{
+				{re: springEnvRe, nameGroup: 1, defaultGroup: 2},
+			}, add)
+		case LanguagePHP:
+			scanEnvRules(dir, root, m.Name, []string{".php"}, []envRule{
+				{re: phpEnvRe, nameGroup: 1, defaultGroup: 2, numberGroup: 3},
+			}, add)
+		case LanguageRuby:
+			scanEnvRules(dir, root, m.Name, []string{".rb", ".yml"}, []envRule{
+				{re: rubyEnvIndexRe, nameGroup: 1},
+				{re: rubyEnvFetchRe, nameGroup: 1, defaultGroup: 2, numberGroup: 3},
+			}, add)
+		}
+	}
+
+	// .env.example и аналоги в корне репозитория — задокументированные значения
+	for _, name := range []string{".env.example", ".env.sample", ".env.dist", ".env.template"} {
+		path := filepath.Join(root, name)
+		if !fileExists(path) {
+			continue
+		}
+		forEachLine(path, func(line int, text string) {
+			if m := dotenvLineRe.FindStringSubmatch(text); m != nil {
+				value := strings.Trim(strings.TrimSpace(stripInlineComment(m[2])), `"'`)
+				add("", m[1], value, value != "", relSource(root, path, line))
+			}
+		})
+	}
+
+	result.EnvVars = result.EnvVars[:0]
+	for _, v := range inventory {
+		v.Required = !v.HasDefault && !optional[v.Name]
+		sort.Strings(v.Modules)
+		result.EnvVars = append(result.EnvVars, *v)
+	}
+	sort.Slice(result.EnvVars, func(i, j int) bool { return result.EnvVars[i].Name < result.EnvVars[j].Name })
+}
+
+// scanGoEnv ищет os.Getenv("X") / os.LookupEnv("X") и хелперы вида getEnv("X", "default");
+// переменные из LookupEnv передаются в markOptional.
+func scanGoEnv(dir, root, module string, add func(module, name, def string, hasDef bool, source string), markOptional func(name string)) {
+	fset := token.NewFileSet()
+	walkSourceFiles(dir, func(path string) {
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			var fn string
+			switch fun := call.Fun.(type) {
+			case *ast.SelectorExpr:
+				fn = fun.Sel.Name
+			case *ast.Ident:
+				fn = fun.Name
+			default:
+				return true
+			}
+			name, ok := goStringLit(call.Args[0])
+			if !ok {
+				return true
+			}
+			source := relSource(root, path, fset.Position(call.Pos()).Line)
+			switch {
+			case fn == "Getenv":
+				add(module, name, "", false, source)
+			case fn == "LookupEnv":
+				add(module, name, "", false, source)
+				markOptional(name)
+			case isGoEnvWriter(fn):
+				// os.Setenv("TZ", "UTC") задаёт переменную сама программа — это не конфигурация
+			case strings.Contains(strings.ToLower(fn), "env") && len(call.Args) >= 2:
+				def, ok := goStringLit(call.Args[1])
+				add(module, name, def, ok, source)
+			}
+			return true
+		})
+	})
+}
+
+// isGoEnvWriter — os.Setenv/Unsetenv/Clearenv и хелперы вида setEnv, которые пишут окружение, а не читают.
+func isGoEnvWriter(fn string) bool {
+	lower := strings.ToLower(fn)
+	for _, prefix := range []string{"set", "unset", "clear"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func goStringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	switch lit.Kind {
+	case token.STRING:
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	case token.INT:
+		return lit.Value, true
+	}
+	return "", false
+}
+
+func scanEnvRules(dir, root, module string, exts []string, rules []envRule, add func(module, name, def string, hasDef bool, source string)) {
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+	walkSourceFiles(dir, func(path string) {
+		if !containsString(exts, filepath.Ext(path)) || isTestFile(path) {
+			return
+		}
+		forEachLine(path, func(line int, text string) {
+			for _, r := range rules {
+				for _, m := range r.re.FindAllStringSubmatch(text, -1) {
+					def, hasDef := "", false
+					if r.defaultGroup > 0 && m[r.defaultGroup] != "" {
+						def, hasDef = m[r.defaultGroup], true
+					} else if r.numberGroup > 0 && m[r.numberGroup] != "" {
+						def, hasDef = m[r.numberGroup], true
+					} else if r.re == springEnvRe && strings.Contains(m[0], ":") {
+						// ${X:} — пустое значение по умолчанию тоже значение
+						hasDef = true
+					}
+					add(module, m[r.nameGroup], def, hasDef, relSource(root, path, line))
+				}
+			}
+		})
+	})
+}
+
+// isTestFile отсекает тесты: переменные из них не нужны в рантайме
+func isTestFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "test_") || strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), "_test") ||
+		strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") || strings.HasSuffix(name, "_spec.rb") ||
+		strings.Contains(filepath.ToSlash(path), "/src/test/")
+}
+
+func isSecretName(name string) bool {
+	for _, marker := range secretMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, "_KEY")
+}
+
+func stripInlineComment(v string) string {
+	if i := strings.Index(v, " #"); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
@@ -35,8 +35,11 @@ func AnalyzeGlobalStats(result *ProjectAnalysisResult, root string) {
 
 		if name == "dockerfile" || strings.HasPrefix(name, "docker-compose") {
 			infraMap["Docker"] = true
-		} else if name == "kustomization.yaml" || strings.HasSuffix(name, "chart.yaml") {
+		} else if name == "kustomization.yaml" || name == "kustomization.yml" || strings.HasSuffix(name, "chart.yaml") {
 			infraMap["Kubernetes"] = true
+			if strings.HasPrefix(name, "kustomization.") {
+				result.KustomizeFiles = append(result.KustomizeFiles, filepath.ToSlash(rel))
+			}
 		} else if strings.HasPrefix(rel, ".github/workflows") {
 			infraMap["GitHub Actions"] = true
 		} else if name == ".gitlab-ci.yml" {
@@ -1,9 +1,13 @@
 package analyzer
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"golang.org/x/mod/modfile"
@@ -47,8 +51,13 @@ func AnalyzeGoModule(result *ProjectAnalysisResult, start string) {
 				module.Name = f.Module.Mod.Path
 			}
 			if f.Go != nil {
-				module.LanguageVersion = f.Go.Version
-				module.BuilderImage = "golang:" + f.Go.Version + "-alpine"
+				// go 1.22.3 — минимальная версия; теги golang:<X.Y> есть всегда, патч-теги — не для любой версии
+				module.VersionConstraint = ">=" + f.Go.Version
+				if f.Toolchain != nil {
+					module.VersionConstraint = ">=" + strings.TrimPrefix(f.Toolchain.Name, "go")
+				}
+				module.LanguageVersion = goMinorVersion(f.Go.Version)
+				module.BuilderImage = "golang:" + module.LanguageVersion + "-alpine"
 			}
 
 			for _, req := range f.Require {
@@ -68,9 +77,108 @@ func AnalyzeGoModule(result *ProjectAnalysisResult, start string) {
 				}
 			}
 
+			// Бинарники: все каталоги с package main (обычно cmd/*)
+			module.BuildTargets = findGoMainPackages(filepath.Dir(path), module.Name)
+			switch len(module.BuildTargets) {
+			case 0:
+				// Библиотека — собирать нечего, только проверяем компиляцию
+				module.BuildCommand = "go build ./..."
+			case 1:
+				t := module.BuildTargets[0]
+				module.BuildCommand = "go build -o " + t.Name + " " + t.Path
+				module.ArtifactPath = t.Name
+			default:
+				paths := make([]string, 0, len(module.BuildTargets))
+				for _, t := range module.BuildTargets {
+					paths = append(paths, t.Path)
+				}
+				// go build -o <dir>/ с несколькими main-пакетами кладёт каждый бинарник в каталог
+				module.BuildCommand = "go build -o bin/ " + strings.Join(paths, " ")
+				module.ArtifactPath = "bin/"
+			}
+
 			result.Modules = append(result.Modules, module)
 			return filepath.SkipDir
 		}
 		return nil
 	})
 }
+
+// findGoMainPackages ищет в модуле каталоги, где объявлены package main и func main.
+// Вложенные модули (свой go.mod), testdata и скрытые каталоги пропускаются.
+// Имя бинарника — имя каталога, для корня модуля — последний сегмент пути модуля.
+func findGoMainPackages(moduleDir string, modulePath string) []BuildTarget {
+	fset := token.NewFileSet()
+	seen := make(map[string]bool)
+	var targets []BuildTarget
+
+	_ = filepath.WalkDir(moduleDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path == moduleDir {
+				return nil
+			}
+			if shouldSkipDir(name) || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".go") || strings.HasSuffix(d.Name(), "_test.go") {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if seen[dir] {
+			return nil
+		}
+
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil || f.Name.Name != "main" || isIgnoredByBuildTag(f) || !hasMainFunc(f) {
+			return nil
+		}
+		seen[dir] = true
+
+		rel, _ := filepath.Rel(moduleDir, dir)
+		rel = filepath.ToSlash(rel)
+		target := BuildTarget{Name: filepath.Base(dir), Path: "./" + rel}
+		if rel == "." {
+			parts := strings.Split(modulePath, "/")
+			target = BuildTarget{Name: parts[len(parts)-1], Path: "."}
+		}
+		targets = append(targets, target)
+		return nil
+	})
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Path < targets[j].Path })
+	return targets
+}
+
+func hasMainFunc(f *ast.File) bool {
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// isIgnoredByBuildTag отсекает служебные файлы вида "//go:build ignore" (генераторы, tools.go).
+func isIgnoredByBuildTag(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			text := strings.TrimSpace(c.Text)
+			if strings.HasPrefix(text, "//go:build") && (strings.Contains(text, "ignore") || strings.Contains(text, "tools")) {
+				return true
+			}
+		}
+	}
+	return false
+}
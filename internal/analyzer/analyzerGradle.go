@@ -0,0 +1,529 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultGradleVersion — версия Gradle, если в проекте нет wrapper-а
+const defaultGradleVersion = "8.5"
+
+var (
+	gradleQuotedRe          = regexp.MustCompile(`['"]([^'"]+)['"]`)
+	gradleProjectDirRe      = regexp.MustCompile(`project\(\s*['"](:[^'"]+)['"]\s*\)\.projectDir\s*=\s*(?:file|new\s+File)\(\s*(?:rootDir\s*,\s*)?['"]([^'"]+)['"]`)
+	gradleRootNameRe        = regexp.MustCompile(`rootProject\.name\s*=\s*['"]([^'"]+)['"]`)
+	gradleToolchainRe       = regexp.MustCompile(`languageVersion\s*(?:=|\.set\()\s*JavaLanguageVersion\.of\(\s*['"]?(\d+)`)
+	gradleJvmToolchainRe    = regexp.MustCompile(`jvmToolchain\(\s*(\d+)`)
+	gradleSourceCompatRe    = regexp.MustCompile(`sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_|JavaVersion\.toVersion\()?['"]?(\d+(?:[._]\d+)?)`)
+	gradlePluginIdRe        = regexp.MustCompile(`\bid\s*\(?\s*['"]([\w.\-]+)['"]\s*\)?\s*(?:\bversion\s*\(?\s*['"]([^'"]+)['"])?`)
+	gradleApplyPluginRe     = regexp.MustCompile(`apply\s*\(?\s*plugin\s*[:=]\s*['"]([\w.\-]+)['"]`)
+	gradleAliasPluginRe     = regexp.MustCompile(`alias\(\s*libs\.plugins\.([\w.]+)\s*\)`)
+	gradleCorePluginRe      = regexp.MustCompile("^\\s*`?(application|war)`?\\s*$")
+	gradleCatalogDepRe      = regexp.MustCompile(`\blibs\.([\w.]+)`)
+	gradleProjectDepRe      = regexp.MustCompile(`project\(\s*(?:path\s*[:=]\s*)?['"](:[^'"]+)['"]`)
+	gradleTypesafeDepRe     = regexp.MustCompile(`\bprojects\.([\w.]+)`)
+	gradleWrapperRe         = regexp.MustCompile(`gradle-([\d.]+(?:-[\w.]+)?)-(?:bin|all)\.zip`)
+	gradleApplyFalseRe      = regexp.MustCompile(`\bapply\s*\(?\s*false`)
+	gradleBootJarDisabledRe = regexp.MustCompile(`bootJar[^{\n]*\{[^}]*enabled\s*(?:=|\.set\()\s*false`)
+)
+
+// gradleProject — один проект Gradle-сборки (корневой или подключённый через include).
+type gradleProject struct {
+	path      string // ":", ":services:api"
+	dir       string
+	buildFile string
+	content   string
+	plugins   map[string]string // применённые плагины: id -> версия (может быть пустой)
+	declared  map[string]string // все объявленные, включая apply false
+}
+
+// gradleCatalog — gradle/libs.versions.toml: алиасы библиотек и плагинов в координаты.
+type gradleCatalog struct {
+	libraries map[string]string // "spring.boot.starter.web" -> "org.springframework.boot:spring-boot-starter-web"
+	plugins   map[string][2]string
+}
+
+// analyzeGradleBuilds находит Gradle-сборки: settings.gradle(.kts) задаёт корень и подпроекты,
+// build.gradle без settings считается одиночным проектом.
+func analyzeGradleBuilds(result *ProjectAnalysisResult, start string) {
+	var settingsDirs []string
+	var buildFiles []string
+
+	_ = filepath.WalkDir(start, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		// Ограничение глубины для оптимизации
+		rel, _ := filepath.Rel(start, path)
+		if d.IsDir() && strings.Count(rel, string(os.PathSeparator)) > 4 {
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch d.Name() {
+		case "settings.gradle", "settings.gradle.kts":
+			settingsDirs = append(settingsDirs, filepath.Dir(path))
+		case "build.gradle", "build.gradle.kts":
+			buildFiles = append(buildFiles, path)
+		}
+		return nil
+	})
+
+	covered := make(map[string]bool)
+	for _, dir := range settingsDirs {
+		if covered[dir] {
+			continue
+		}
+		projects := gradleSettingsProjects(dir)
+		for _, p := range projects {
+			covered[p.dir] = true
+		}
+		addGradleBuild(result, start, dir, projects)
+	}
+	for _, path := range buildFiles {
+		dir := filepath.Dir(path)
+		if covered[dir] {
+			continue
+		}
+		covered[dir] = true
+		addGradleBuild(result, start, dir, []*gradleProject{{path: ":", dir: dir}})
+	}
+}
+
+// gradleSettingsProjects читает include(...) и project(':x').projectDir из settings.gradle(.kts).
+func gradleSettingsProjects(root string) []*gradleProject {
+	projects := []*gradleProject{{path: ":", dir: root}}
+	settings := ""
+	for _, name := range []string{"settings.gradle.kts", "settings.gradle"} {
+		if b, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			settings = string(b)
+			break
+		}
+	}
+
+	customDirs := make(map[string]string)
+	for _, m := range gradleProjectDirRe.FindAllStringSubmatch(settings, -1) {
+		customDirs[m[1]] = m[2]
+	}
+
+	seen := map[string]bool{":": true}
+	for _, include := range gradleIncludes(settings) {
+		path := include
+		if !strings.HasPrefix(path, ":") {
+			path = ":" + path
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		rel := strings.ReplaceAll(strings.TrimPrefix(path, ":"), ":", "/")
+		if custom, ok := customDirs[path]; ok {
+			rel = custom
+		}
+		projects = append(projects, &gradleProject{path: path, dir: filepath.Join(root, filepath.FromSlash(rel))})
+	}
+	return projects
+}
+
+// gradleIncludes собирает аргументы include, в том числе многострочных:
+// include("a", "b"), include 'a', 'b' и include(\n "a",\n "b"\n).
+func gradleIncludes(settings string) []string {
+	var out []string
+	lines := strings.Split(settings, "\n")
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "include") || strings.HasPrefix(trimmed, "includeBuild") {
+			continue
+		}
+		args := strings.TrimSpace(strings.TrimPrefix(trimmed, "include"))
+		if args != "" && args[0] != '(' && args[0] != '\'' && args[0] != '"' {
+			continue
+		}
+		open := strings.HasPrefix(args, "(")
+		for (open && !strings.Contains(args, ")") || strings.HasSuffix(args, ",")) && i+1 < len(lines) {
+			i++
+			args += " " + strings.TrimSpace(lines[i])
+		}
+		for _, m := range gradleQuotedRe.FindAllStringSubmatch(args, -1) {
+			out = append(out, m[1])
+		}
+	}
+	return out
+}
+
+// addGradleBuild разбирает проекты одной сборки и добавляет в результат исполняемые модули и граф.
+func addGradleBuild(result *ProjectAnalysisResult, start, root string, projects []*gradleProject) {
+	catalog := loadGradleCatalog(root)
+	for _, p := range projects {
+		for _, name := range []string{"build.gradle.kts", "build.gradle"} {
+			path := filepath.Join(p.dir, name)
+			if b, err := os.ReadFile(path); err == nil {
+				p.buildFile, p.content = path, string(b)
+				break
+			}
+		}
+		p.plugins, p.declared = gradlePlugins(p.content, catalog)
+	}
+	rootProject := projects[0]
+	if rootProject.buildFile == "" && len(projects) == 1 {
+		return
+	}
+
+	gradleVersion := gradleWrapperVersion(root)
+	cmd := "gradle"
+	if fileExists(filepath.Join(root, "gradlew")) {
+		cmd = "./gradlew"
+	}
+	buildRoot := relDir(start, root)
+	multi := len(projects) > 1
+
+	byPath := make(map[string]*gradleProject)
+	for _, p := range projects {
+		byPath[p.path] = p
+	}
+
+	graph := BuildGraph{BuildTool: BuildToolGradle, Root: buildRoot}
+	var modules []*ProjectModule
+	for _, p := range projects {
+		if p.buildFile == "" && p != rootProject {
+			continue
+		}
+		module := p.toModule(rootProject, catalog, gradleVersion)
+		task := p.artifactTask()
+		node := BuildGraphNode{
+			Name:      p.path,
+			Path:      relDir(root, p.dir),
+			Packaging: "jar",
+			Framework: module.Framework,
+			Runnable:  task != "",
+			DependsOn: p.projectDependencies(byPath),
+		}
+		if _, ok := p.plugins["war"]; ok {
+			node.Packaging = "war"
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		if !node.Runnable {
+			continue
+		}
+		module.BuildRoot = buildRoot
+		module.BuildTask = task
+		if multi && p != rootProject {
+			module.ReactorModule = node.Path
+			module.BuildTask = p.path + ":" + task
+		}
+		module.BuildCommand, module.TestCommand = gradleCommands(cmd, buildRoot, module.BuildTask, module.ReactorModule, p.path)
+		module.ArtifactPath = joinRel(buildRoot, joinRel(module.ReactorModule, p.artifactPath()))
+		modules = append(modules, module)
+	}
+	if multi {
+		result.BuildGraphs = append(result.BuildGraphs, graph)
+	}
+
+	if len(modules) == 0 {
+		// Исполняемых проектов нет: корень репозитория или модуль с фреймворком собираем целиком (прежний фильтр шума)
+		module := rootProject.toModule(rootProject, catalog, gradleVersion)
+		if root != filepath.Clean(start) && module.Framework == "" {
+			return
+		}
+		module.BuildRoot = buildRoot
+		module.BuildTask = "build"
+		module.BuildCommand, module.TestCommand = gradleCommands(cmd, buildRoot, "build -x test", "", ":")
+		module.ArtifactPath = joinRel(buildRoot, "build/libs/*.jar")
+		if multi {
+			module.ArtifactPath = joinRel(buildRoot, "**/build/libs/*.jar")
+		}
+		modules = append(modules, module)
+	}
+	result.Modules = append(result.Modules, modules...)
+}
+
+func gradleCommands(cmd, buildRoot, task, reactorModule, projectPath string) (string, string) {
+	if buildRoot != "." {
+		cmd += " -p " + buildRoot
+	}
+	test := cmd + " test"
+	if reactorModule != "" {
+		test = cmd + " " + projectPath + ":test"
+	}
+	return cmd + " " + task, test
+}
+
+// toModule переносит проект Gradle в ProjectModule; версия Java наследуется от корневого проекта.
+func (p *gradleProject) toModule(root *gradleProject, catalog *gradleCatalog, gradleVersion string) *ProjectModule {
+	module := &ProjectModule{
+		Name:         filepath.Base(p.dir),
+		ModulePath:   p.buildFile,
+		Language:     LanguageJava,
+		BuildTool:    BuildToolGradle,
+		ArtifactPath: "./build/libs/*.jar",
+		AppPort:      "8080",
+	}
+	if p.path == ":" {
+		if b, err := os.ReadFile(filepath.Join(p.dir, "settings.gradle.kts")); err == nil {
+			if m := gradleRootNameRe.FindStringSubmatch(string(b)); m != nil {
+				module.Name = m[1]
+			}
+		} else if b, err := os.ReadFile(filepath.Join(p.dir, "settings.gradle")); err == nil {
+			if m := gradleRootNameRe.FindStringSubmatch(string(b)); m != nil {
+				module.Name = m[1]
+			}
+		}
+	} else {
+		module.Name = p.path[strings.LastIndex(p.path, ":")+1:]
+	}
+	if module.ModulePath == "" {
+		module.ModulePath = filepath.Join(p.dir, "build.gradle")
+	}
+
+	module.VersionConstraint = gradleJavaVersion(p.content)
+	if module.VersionConstraint == "" && root != p {
+		module.VersionConstraint = gradleJavaVersion(root.content)
+	}
+	module.LanguageVersion = module.VersionConstraint
+	if module.LanguageVersion == "" {
+		module.LanguageVersion = "17"
+	}
+	module.BuilderImage = "gradle:" + gradleVersion + "-jdk" + module.LanguageVersion
+	module.RuntimeImage = "eclipse-temurin:" + module.LanguageVersion + "-jre"
+
+	module.Dependencies = gradleDependencies(p.content, catalog)
+	switch {
+	case p.hasPlugin("org.springframework.boot"):
+		module.Framework, module.FrameworkVersion = "Spring Boot", p.pluginVersion("org.springframework.boot", root)
+	case p.hasPlugin("io.quarkus"):
+		module.Framework, module.FrameworkVersion = "Quarkus", p.pluginVersion("io.quarkus", root)
+	default:
+		// стартеры без плагина (библиотеки на Spring Boot)
+		for _, dep := range module.Dependencies {
+			if strings.HasPrefix(dep, "org.springframework.boot:") {
+				module.Framework = "Spring Boot"
+				break
+			}
+		}
+	}
+	return module
+}
+
+func (p *gradleProject) hasPlugin(id string) bool {
+	_, ok := p.plugins[id]
+	return ok
+}
+
+// pluginVersion — версия плагина в самом проекте или в plugins {} корневого build-файла.
+func (p *gradleProject) pluginVersion(id string, root *gradleProject) string {
+	if v := p.declared[id]; v != "" {
+		return v
+	}
+	return root.declared[id]
+}
+
+// artifactTask — задача, собирающая исполняемый артефакт: bootJar, quarkusBuild, installDist (application), war.
+// Проект со Spring Boot плагином, но с отключённым bootJar — библиотека.
+func (p *gradleProject) artifactTask() string {
+	switch {
+	case p.hasPlugin("org.springframework.boot") && !gradleBootJarDisabledRe.MatchString(p.content):
+		return "bootJar"
+	case p.hasPlugin("io.quarkus"):
+		return "quarkusBuild"
+	case p.hasPlugin("application"):
+		return "installDist"
+	case p.hasPlugin("war"):
+		return "war"
+	}
+	return ""
+}
+
+func (p *gradleProject) artifactPath() string {
+	switch p.artifactTask() {
+	case "installDist":
+		return "build/install/" + filepath.Base(p.dir) + "/"
+	case "quarkusBuild":
+		return "build/quarkus-app/"
+	case "war":
+		return "build/libs/*.war"
+	}
+	return "build/libs/*.jar"
+}
+
+// projectDependencies — project(":common") и typesafe-аксессоры projects.common.
+func (p *gradleProject) projectDependencies(byPath map[string]*gradleProject) []string {
+	var out []string
+	add := func(path string) {
+		if _, ok := byPath[path]; ok && path != p.path && !containsString(out, path) {
+			out = append(out, path)
+		}
+	}
+	for _, m := range gradleProjectDepRe.FindAllStringSubmatch(p.content, -1) {
+		add(m[1])
+	}
+	for _, m := range gradleTypesafeDepRe.FindAllStringSubmatch(p.content, -1) {
+		// projects.services.api -> :services:api; имена в аксессорах — camelCase от kebab-case
+		for path := range byPath {
+			if gradleAccessor(path) == m[1] {
+				add(path)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func gradleAccessor(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, ":"), ":")
+	for i, part := range parts {
+		words := strings.FieldsFunc(part, func(r rune) bool { return r == '-' || r == '_' })
+		for j := 1; j < len(words); j++ {
+			words[j] = strings.ToUpper(words[j][:1]) + words[j][1:]
+		}
+		parts[i] = strings.Join(words, "")
+	}
+	return strings.Join(parts, ".")
+}
+
+// gradleJavaVersion: toolchain languageVersion > kotlin jvmToolchain > sourceCompatibility.
+func gradleJavaVersion(content string) string {
+	for _, re := range []*regexp.Regexp{gradleToolchainRe, gradleJvmToolchainRe, gradleSourceCompatRe} {
+		if m := re.FindStringSubmatch(content); m != nil {
+			v := strings.ReplaceAll(m[1], "_", ".")
+			return strings.TrimPrefix(v, "1.")
+		}
+	}
+	return ""
+}
+
+// gradlePlugins собирает применённые плагины из plugins {}, apply plugin и alias(libs.plugins.x).
+// Объявления с apply false (версии для подпроектов) попадают в declared, но не в applied.
+func gradlePlugins(content string, catalog *gradleCatalog) (applied, declared map[string]string) {
+	applied = make(map[string]string)
+	declared = make(map[string]string)
+	add := func(id, version, line string) {
+		declared[id] = version
+		if !gradleApplyFalseRe.MatchString(line) {
+			applied[id] = version
+		}
+	}
+	for _, line := range strings.Split(content, "\n") {
+		for _, m := range gradlePluginIdRe.FindAllStringSubmatch(line, -1) {
+			add(m[1], m[2], line)
+		}
+		for _, m := range gradleApplyPluginRe.FindAllStringSubmatch(line, -1) {
+			if _, ok := applied[m[1]]; !ok {
+				add(m[1], "", line)
+			}
+		}
+		for _, m := range gradleAliasPluginRe.FindAllStringSubmatch(line, -1) {
+			if p, ok := catalog.plugins[m[1]]; ok {
+				add(p[0], p[1], line)
+			}
+		}
+		if m := gradleCorePluginRe.FindStringSubmatch(line); m != nil {
+			add(m[1], "", line)
+		}
+	}
+	return applied, declared
+}
+
+// gradleDependencies — координаты 'group:artifact:version' и алиасы libs.x.y из version catalog.
+func gradleDependencies(content string, catalog *gradleCatalog) []string {
+	var deps []string
+	for _, m := range gradleDependencyRe.FindAllStringSubmatch(content, -1) {
+		if dep := m[1] + ":" + m[2]; !containsString(deps, dep) {
+			deps = append(deps, dep)
+		}
+	}
+	for _, m := range gradleCatalogDepRe.FindAllStringSubmatch(content, -1) {
+		if strings.HasPrefix(m[1], "plugins.") || strings.HasPrefix(m[1], "versions.") || strings.HasPrefix(m[1], "bundles.") {
+			continue
+		}
+		if dep, ok := catalog.libraries[m[1]]; ok && !containsString(deps, dep) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// gradleWrapperVersion — точная версия Gradle из gradle/wrapper/gradle-wrapper.properties.
+func gradleWrapperVersion(root string) string {
+	b, err := os.ReadFile(filepath.Join(root, "gradle", "wrapper", "gradle-wrapper.properties"))
+	if err != nil {
+		return defaultGradleVersion
+	}
+	if m := gradleWrapperRe.FindStringSubmatch(string(b)); m != nil {
+		return m[1]
+	}
+	return defaultGradleVersion
+}
+
+// loadGradleCatalog читает gradle/libs.versions.toml; при ошибке возвращает пустой каталог.
+func loadGradleCatalog(root string) *gradleCatalog {
+	catalog := &gradleCatalog{libraries: map[string]string{}, plugins: map[string][2]string{}}
+	b, err := os.ReadFile(filepath.Join(root, "gradle", "libs.versions.toml"))
+	if err != nil {
+		return catalog
+	}
+	doc, err := parseTOML(b)
+	if err != nil {
+		return catalog
+	}
+	versions := tomlTableAt(doc, "versions")
+	version := func(entry map[string]any) string {
+		if v, ok := entry["version"].(string); ok {
+			return v
+		}
+		if ref := tomlString(entry, "version", "ref"); ref != "" && versions != nil {
+			if v, ok := versions[ref].(string); ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	for alias, raw := range tomlTableAt(doc, "libraries") {
+		key := gradleCatalogKey(alias)
+		switch v := raw.(type) {
+		case string:
+			parts := strings.Split(v, ":")
+			if len(parts) >= 2 {
+				catalog.libraries[key] = parts[0] + ":" + parts[1]
+			}
+		case map[string]any:
+			if module, ok := v["module"].(string); ok {
+				catalog.libraries[key] = module
+			} else if group, ok := v["group"].(string); ok {
+				name, _ := v["name"].(string)
+				catalog.libraries[key] = fmt.Sprintf("%s:%s", group, name)
+			}
+		}
+	}
+	for alias, raw := range tomlTableAt(doc, "plugins") {
+		key := gradleCatalogKey(alias)
+		switch v := raw.(type) {
+		case string:
+			parts := strings.SplitN(v, ":", 2)
+			if len(parts) == 2 {
+				catalog.plugins[key] = [2]string{parts[0], parts[1]}
+			} else {
+				catalog.plugins[key] = [2]string{v, ""}
+			}
+		case map[string]any:
+			if id, ok := v["id"].(string); ok {
+				catalog.plugins[key] = [2]string{id, version(v)}
+			}
+		}
+	}
+	return catalog
+}
+
+// gradleCatalogKey: алиас spring-boot-web доступен в скриптах как libs.spring.boot.web.
+func gradleCatalogKey(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// healthExts — исходники, в которых ищутся маршруты проверки здоровья.
+var healthExts = map[Language][]string{
+	LanguageGo:         {".go"},
+	LanguageJavaScript: {".js", ".mjs", ".cjs", ".ts"},
+	LanguageTypeScript: {".js", ".mjs", ".cjs", ".ts"},
+	LanguagePython:     {".py"},
+	LanguageJava:       {".java", ".kt"},
+	LanguageKotlin:     {".java", ".kt"},
+	LanguagePHP:        {".php"},
+	LanguageRuby:       {".rb"},
+}
+
+var (
+	// строковый литерал маршрута: "/healthz", '/api/v1/health', `/ping`; Django пишет путь без ведущего "/": "health/"
+	healthLiteralRe = regexp.MustCompile("[\"'`](/(?:api/)?(?:v\\d+/)?(?:healthz|health|healthcheck|_health|livez|readyz|ping)/?|(?:api/)?(?:healthz|health|healthcheck)/)[\"'`]")
+	// Laravel 11: ->withRouting(health: '/up')
+	laravelHealthRe = regexp.MustCompile(`\bhealth\s*:\s*['"](/?[\w/-]+)['"]`)
+	// Rails 7.1: get "up" => "rails/health#show"
+	railsHealthRe = regexp.MustCompile(`['"](/?[\w/-]+)['"]\s*=>\s*['"]rails/health#show['"]`)
+)
+
+// healthRank — чем меньше, тем лучше маршрут подходит для HEALTHCHECK (проверка живости, а не готовности).
+var healthRank = map[string]int{
+	"healthz": 1, "health": 2, "healthcheck": 3, "_health": 4, "livez": 5, "readyz": 6, "ping": 7,
+}
+
+// DetectHealthEndpoints находит HTTP-эндпоинт проверки здоровья каждого модуля: Spring Boot Actuator
+// по зависимости, health-маршруты Laravel и Rails, иначе строковые литералы маршрутов (/healthz, /health,
+// /livez, ...) в исходниках модуля без тестов. Статика, которую раздаёт nginx, не проверяется.
+func DetectHealthEndpoints(result *ProjectAnalysisResult) {
+	for _, m := range result.Modules {
+		if m.StaticSite {
+			continue
+		}
+		if hasActuator(m.Dependencies) {
+			m.HealthPath = "/actuator/health"
+			continue
+		}
+		exts := healthExts[m.Language]
+		if len(exts) == 0 {
+			continue
+		}
+		dir := filepath.Dir(m.ModulePath)
+		best, bestRank := "", 0
+		walkSourceFiles(dir, func(p string) {
+			if !containsString(exts, filepath.Ext(p)) || isTestSource(dir, p) || deepestModule(result.Modules, p) != m {
+				return
+			}
+			forEachLine(p, func(_ int, text string) {
+				if route, rank, ok := healthRoute(text); ok && (best == "" || rank < bestRank || (rank == bestRank && len(route) < len(best))) {
+					best, bestRank = route, rank
+				}
+			})
+		})
+		m.HealthPath = best
+	}
+}
+
+// healthRoute — маршрут в строке исходника и его ранг; маршруты фреймворков важнее литералов.
+func healthRoute(text string) (string, int, bool) {
+	for _, re := range []*regexp.Regexp{laravelHealthRe, railsHealthRe} {
+		if sub := re.FindStringSubmatch(text); sub != nil {
+			return "/" + strings.Trim(sub[1], "/"), 0, true
+		}
+	}
+	sub := healthLiteralRe.FindStringSubmatch(text)
+	if sub == nil {
+		return "", 0, false
+	}
+	route := "/" + strings.Trim(sub[1], "/")
+	return route, healthRank[path.Base(route)], true
+}
+
+func hasActuator(deps []string) bool {
+	for _, d := range deps {
+		if d == "spring-boot-starter-actuator" || strings.HasSuffix(d, ":spring-boot-starter-actuator") || strings.Contains(d, ":spring-boot-starter-actuator:") {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestSource: тесты часто обращаются к /health — маршрутом приложения это не считается.
+func isTestSource(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, seg := range strings.Split(path.Dir(rel), "/") {
+		switch seg {
+		case "test", "tests", "spec", "__tests__", "e2e", "testdata":
+			return true
+		}
+	}
+	name := path.Base(rel)
+	return strings.HasSuffix(name, "_test.go") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") ||
+		strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test.py") || strings.HasSuffix(name, "_spec.rb") ||
+		strings.HasSuffix(name, "Test.java") || strings.HasSuffix(name, "Test.kt")
+}
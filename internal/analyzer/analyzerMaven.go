@@ -0,0 +1,465 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type PomProject struct {
+	GroupId              string          `xml:"groupId"`
+	ArtifactId           string          `xml:"artifactId"`
+	Version              string          `xml:"version"`
+	Packaging            string          `xml:"packaging"`
+	Parent               PomParent       `xml:"parent"`
+	Properties           PomProperties   `xml:"properties"`
+	Modules              []string        `xml:"modules>module"`
+	Dependencies         []PomDependency `xml:"dependencies>dependency"`
+	DependencyManagement []PomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins              []PomPlugin     `xml:"build>plugins>plugin"`
+}
+type PomParent struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	// RelativePath: nil — элемента нет (по умолчанию ../pom.xml), "" — <relativePath/>, родитель только из репозитория
+	RelativePath *string `xml:"relativePath"`
+}
+type PomDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Scope      string `xml:"scope"`
+}
+type PomPlugin struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// PomProperties — произвольные <properties>: имена элементов заранее неизвестны.
+type PomProperties struct {
+	Entries []PomProperty `xml:",any"`
+}
+type PomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+var pomPropertyRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// javaVersionProperties — откуда берём версию Java, по убыванию приоритета
+var javaVersionProperties = []string{"maven.compiler.release", "java.version", "maven.compiler.source", "maven.compiler.target"}
+
+// mavenPom — разобранный pom.xml вместе со связями внутри репозитория.
+type mavenPom struct {
+	path       string
+	dir        string
+	pom        PomProject
+	props      map[string]string
+	parent     *mavenPom // локальный родитель (<parent> + relativePath)
+	aggregator *mavenPom // pom, в <modules> которого перечислен этот
+	children   []*mavenPom
+}
+
+// analyzeMavenReactors находит все pom.xml, связывает их через <parent> и <modules>
+// и добавляет в результат исполняемые модули каждого reactor-а вместе с графом сборки.
+func analyzeMavenReactors(result *ProjectAnalysisResult, start string) {
+	var poms []*mavenPom
+	byPath := make(map[string]*mavenPom)
+
+	_ = filepath.WalkDir(start, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		// Ограничение глубины для оптимизации
+		rel, _ := filepath.Rel(start, path)
+		if d.IsDir() && strings.Count(rel, string(os.PathSeparator)) > 4 {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "pom.xml" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		p := &mavenPom{path: filepath.Clean(path), dir: filepath.Dir(filepath.Clean(path))}
+		if err := xml.Unmarshal(content, &p.pom); err != nil {
+			return nil
+		}
+		p.props = make(map[string]string)
+		for _, e := range p.pom.Properties.Entries {
+			p.props[e.XMLName.Local] = strings.TrimSpace(e.Value)
+		}
+		poms = append(poms, p)
+		byPath[p.path] = p
+		return nil
+	})
+
+	linkMavenPoms(poms, byPath)
+
+	for _, p := range poms {
+		if p.aggregator != nil {
+			continue
+		}
+		if len(p.children) == 0 {
+			addStandalonePom(result, start, p)
+			continue
+		}
+		addMavenReactor(result, start, p)
+	}
+}
+
+// linkMavenPoms проставляет локальных родителей и агрегаторы.
+func linkMavenPoms(poms []*mavenPom, byPath map[string]*mavenPom) {
+	for _, p := range poms {
+		for _, m := range p.pom.Modules {
+			child := byPath[pomFilePath(p.dir, strings.TrimSpace(m))]
+			if child != nil && child != p && child.aggregator == nil {
+				child.aggregator = p
+				p.children = append(p.children, child)
+			}
+		}
+
+		par := p.pom.Parent
+		if par.ArtifactId == "" {
+			continue
+		}
+		rel := "../pom.xml"
+		if par.RelativePath != nil {
+			rel = strings.TrimSpace(*par.RelativePath)
+		}
+		if rel != "" {
+			if cand := byPath[pomFilePath(p.dir, rel)]; cand != nil && cand.pom.ArtifactId == par.ArtifactId {
+				p.parent = cand
+				continue
+			}
+		}
+		// relativePath не совпал — ищем родителя по координатам среди pom.xml репозитория
+		for _, cand := range poms {
+			if cand != p && cand.pom.ArtifactId == par.ArtifactId && cand.groupID() == par.GroupId {
+				p.parent = cand
+				break
+			}
+		}
+	}
+}
+
+func pomFilePath(dir, rel string) string {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if !strings.HasSuffix(path, ".xml") {
+		path = filepath.Join(path, "pom.xml")
+	}
+	return filepath.Clean(path)
+}
+
+func (p *mavenPom) groupID() string {
+	if p.pom.GroupId != "" {
+		return p.resolve(p.pom.GroupId)
+	}
+	return p.resolve(p.pom.Parent.GroupId)
+}
+
+func (p *mavenPom) artifactID() string {
+	return p.resolve(p.pom.ArtifactId)
+}
+
+func (p *mavenPom) version() string {
+	if p.pom.Version != "" {
+		return p.resolve(p.pom.Version)
+	}
+	return p.resolve(p.pom.Parent.Version)
+}
+
+func (p *mavenPom) packaging() string {
+	if v := p.resolve(p.pom.Packaging); v != "" {
+		return v
+	}
+	return "jar"
+}
+
+// property ищет свойство в самом pom и вверх по цепочке локальных родителей.
+func (p *mavenPom) property(name string) (string, bool) {
+	switch name {
+	case "project.version", "pom.version", "version":
+		return p.version(), true
+	case "project.groupId", "pom.groupId":
+		return p.groupID(), true
+	case "project.artifactId", "pom.artifactId":
+		return p.pom.ArtifactId, true
+	case "project.parent.version", "parent.version":
+		return p.pom.Parent.Version, true
+	case "project.parent.groupId":
+		return p.pom.Parent.GroupId, true
+	}
+	for cur := p; cur != nil; cur = cur.parent {
+		if v, ok := cur.props[name]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// resolve подставляет ${...}; неизвестные свойства остаются как есть.
+func (p *mavenPom) resolve(s string) string {
+	s = strings.TrimSpace(s)
+	for i := 0; i < 10 && strings.Contains(s, "${"); i++ {
+		next := pomPropertyRe.ReplaceAllStringFunc(s, func(m string) string {
+			if v, ok := p.property(m[2 : len(m)-1]); ok {
+				return v
+			}
+			return m
+		})
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+// effectiveDependencies — зависимости модуля и унаследованные от локальных родителей.
+func (p *mavenPom) effectiveDependencies() []PomDependency {
+	var out []PomDependency
+	seen := make(map[string]bool)
+	for cur := p; cur != nil; cur = cur.parent {
+		for _, dep := range cur.pom.Dependencies {
+			dep = PomDependency{
+				GroupId:    p.resolve(dep.GroupId),
+				ArtifactId: p.resolve(dep.ArtifactId),
+				Version:    p.resolve(dep.Version),
+				Type:       dep.Type,
+				Scope:      dep.Scope,
+			}
+			key := dep.GroupId + ":" + dep.ArtifactId
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if dep.Version == "" {
+				dep.Version = p.managedVersion(dep.GroupId, dep.ArtifactId)
+			}
+			out = append(out, dep)
+		}
+	}
+	return out
+}
+
+// managedVersion — версия из <dependencyManagement> модуля или его родителей.
+func (p *mavenPom) managedVersion(group, artifact string) string {
+	for cur := p; cur != nil; cur = cur.parent {
+		for _, dep := range cur.pom.DependencyManagement {
+			if p.resolve(dep.GroupId) == group && p.resolve(dep.ArtifactId) == artifact {
+				return p.resolve(dep.Version)
+			}
+		}
+	}
+	return ""
+}
+
+// effectivePlugins — <build><plugins> модуля и родителей (pluginManagement не наследуется как подключение).
+func (p *mavenPom) effectivePlugins() []PomPlugin {
+	var out []PomPlugin
+	for cur := p; cur != nil; cur = cur.parent {
+		out = append(out, cur.pom.Plugins...)
+	}
+	return out
+}
+
+// javaVersion — maven.compiler.release / java.version / maven.compiler.source с интерполяцией.
+func (p *mavenPom) javaVersion() string {
+	for _, name := range javaVersionProperties {
+		if v, ok := p.property(name); ok {
+			if v = p.resolve(v); v != "" && !strings.Contains(v, "${") {
+				return strings.TrimPrefix(v, "1.")
+			}
+		}
+	}
+	return ""
+}
+
+// framework определяет Spring Boot / Quarkus по внешнему родителю, импортированным BOM и зависимостям.
+func (p *mavenPom) framework() (string, string) {
+	top := p
+	for top.parent != nil {
+		top = top.parent
+	}
+	if top.pom.Parent.ArtifactId == "spring-boot-starter-parent" {
+		return "Spring Boot", top.resolve(top.pom.Parent.Version)
+	}
+
+	name, version := "", ""
+	for cur := p; cur != nil; cur = cur.parent {
+		for _, dep := range cur.pom.DependencyManagement {
+			artifact := p.resolve(dep.ArtifactId)
+			switch {
+			case artifact == "spring-boot-dependencies":
+				return "Spring Boot", p.resolve(dep.Version)
+			case strings.HasPrefix(p.resolve(dep.GroupId), "io.quarkus") && strings.HasSuffix(artifact, "-bom"):
+				name, version = "Quarkus", p.resolve(dep.Version)
+			}
+		}
+	}
+	for _, dep := range p.effectiveDependencies() {
+		switch {
+		case strings.Contains(dep.GroupId, "io.quarkus"):
+			if name == "" || version == "" {
+				name, version = "Quarkus", dep.Version
+			}
+		case strings.Contains(dep.GroupId, "org.springframework.boot") && name == "":
+			return "Spring Boot", dep.Version
+		}
+	}
+	return name, version
+}
+
+// runnable: исполняемый артефакт собирают spring-boot-maven-plugin / quarkus-maven-plugin, war — контейнер сервлетов.
+func (p *mavenPom) runnable() bool {
+	switch p.packaging() {
+	case "war":
+		return true
+	case "jar":
+	default:
+		return false
+	}
+	for _, plugin := range p.effectivePlugins() {
+		switch p.resolve(plugin.ArtifactId) {
+		case "spring-boot-maven-plugin", "quarkus-maven-plugin":
+			return true
+		}
+	}
+	return false
+}
+
+// toModule переносит разобранный pom в ProjectModule.
+func (p *mavenPom) toModule() *ProjectModule {
+	module := &ProjectModule{
+		Name:            filepath.Base(p.dir),
+		ModulePath:      p.path,
+		Language:        LanguageJava,
+		LanguageVersion: "17",
+		BuildTool:       BuildToolMaven,
+		BuildCommand:    "mvn clean package -DskipTests",
+		TestCommand:     "mvn test",
+		ArtifactPath:    "./target/*.jar",
+		AppPort:         "8080",
+	}
+	if a := p.artifactID(); a != "" {
+		module.Name = a
+	}
+	if v := p.javaVersion(); v != "" {
+		module.LanguageVersion, module.VersionConstraint = v, v
+	}
+	module.BuilderImage = "maven:3.9-eclipse-temurin-" + module.LanguageVersion
+	module.RuntimeImage = "eclipse-temurin:" + module.LanguageVersion + "-jre"
+	module.Framework, module.FrameworkVersion = p.framework()
+	for _, dep := range p.effectiveDependencies() {
+		module.Dependencies = append(module.Dependencies, dep.GroupId+":"+dep.ArtifactId)
+	}
+	return module
+}
+
+// addStandalonePom — одиночный pom.xml вне reactor-а. Фильтр шума прежний: корень репозитория,
+// модуль с фреймворком или war; остальное считаем библиотеками/фикстурами внутри монорепо.
+func addStandalonePom(result *ProjectAnalysisResult, start string, p *mavenPom) {
+	module := p.toModule()
+	isRoot := p.dir == filepath.Clean(start)
+	if !isRoot && module.Framework == "" && p.packaging() != "war" && !p.runnable() {
+		return
+	}
+	module.BuildRoot = relDir(start, p.dir)
+	if module.BuildRoot != "." {
+		module.BuildCommand = fmt.Sprintf("mvn -f %s/pom.xml clean package -DskipTests", module.BuildRoot)
+		module.ArtifactPath = module.BuildRoot + "/target/*.jar"
+	}
+	result.Modules = append(result.Modules, module)
+}
+
+// addMavenReactor строит граф модулей reactor-а и добавляет исполняемые модули,
+// собираемые через mvn -pl <module> -am. Если исполняемых нет — добавляется сам агрегатор.
+func addMavenReactor(result *ProjectAnalysisResult, start string, root *mavenPom) {
+	var members []*mavenPom
+	var collect func(p *mavenPom)
+	collect = func(p *mavenPom) {
+		members = append(members, p)
+		for _, c := range p.children {
+			collect(c)
+		}
+	}
+	collect(root)
+
+	byCoords := make(map[string]*mavenPom)
+	for _, p := range members {
+		byCoords[p.groupID()+":"+p.artifactID()] = p
+	}
+
+	graph := BuildGraph{BuildTool: BuildToolMaven, Root: relDir(start, root.dir)}
+	var runnable []*mavenPom
+	for _, p := range members {
+		framework, _ := p.framework()
+		node := BuildGraphNode{
+			Name:      p.artifactID(),
+			Path:      relDir(root.dir, p.dir),
+			Packaging: p.packaging(),
+			Framework: framework,
+			Runnable:  p.runnable(),
+		}
+		for _, dep := range p.effectiveDependencies() {
+			if target, ok := byCoords[dep.GroupId+":"+dep.ArtifactId]; ok && target != p {
+				node.DependsOn = append(node.DependsOn, target.artifactID())
+			}
+		}
+		if node.Runnable && p != root {
+			runnable = append(runnable, p)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	result.BuildGraphs = append(result.BuildGraphs, graph)
+
+	fileArg := ""
+	if graph.Root != "." {
+		fileArg = " -f " + graph.Root + "/pom.xml"
+	}
+	if len(runnable) == 0 {
+		module := root.toModule()
+		module.BuildRoot = graph.Root
+		module.BuildCommand = "mvn" + fileArg + " clean package -DskipTests"
+		module.TestCommand = "mvn" + fileArg + " test"
+		module.ArtifactPath = joinRel(graph.Root, "**/target/*.jar")
+		result.Modules = append(result.Modules, module)
+		return
+	}
+	for _, p := range runnable {
+		module := p.toModule()
+		module.BuildRoot = graph.Root
+		module.ReactorModule = relDir(root.dir, p.dir)
+		module.BuildCommand = fmt.Sprintf("mvn%s -pl %s -am clean package -DskipTests", fileArg, module.ReactorModule)
+		module.TestCommand = fmt.Sprintf("mvn%s -pl %s -am test", fileArg, module.ReactorModule)
+		module.ArtifactPath = joinRel(graph.Root, module.ReactorModule+"/target/*.jar")
+		result.Modules = append(result.Modules, module)
+	}
+}
+
+// relDir — путь каталога относительно base в slash-нотации, "." для самого base.
+func relDir(base, dir string) string {
+	rel, err := filepath.Rel(base, dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func joinRel(root, path string) string {
+	if root == "." || root == "" {
+		return path
+	}
+	return root + "/" + path
+}
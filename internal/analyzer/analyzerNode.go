@@ -4,12 +4,27 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
-func AnalyzeNodeModule(result *ProjectAnalysisResult, start string) {
-	targetFiles := []string{"package.json"}
+type packageJSON struct {
+	Name            string            `json:"name"`
+	Private         bool              `json:"private"`
+	PackageManager  string            `json:"packageManager"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Engines         map[string]string `json:"engines"`
+	// Workspaces — массив glob-ов или объект {"packages": [...]} (yarn classic)
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// nodeServerFrameworks — фреймворки, пакеты с которыми поднимают сервер и деплоятся сами по себе
+var nodeServerFrameworks = map[string]bool{"Express": true, "NestJS": true, "Next.js": true, "Nuxt": true}
 
+func AnalyzeNodeModule(result *ProjectAnalysisResult, start string) {
+	var manifests []string
 	_ = filepath.WalkDir(start, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
@@ -17,78 +32,171 @@ func AnalyzeNodeModule(result *ProjectAnalysisResult, start string) {
 		if d.IsDir() && shouldSkipDir(d.Name()) {
 			return filepath.SkipDir
 		}
+		if !d.IsDir() && d.Name() == "package.json" {
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+	// Корневые манифесты раньше вложенных: workspace-корень должен забрать свои пакеты
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return strings.Count(manifests[i], string(os.PathSeparator)) < strings.Count(manifests[j], string(os.PathSeparator))
+	})
 
-		if !d.IsDir() && containsString(targetFiles, d.Name()) {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil
+	claimed := make(map[string]bool)
+	var plainDirs []string
+	for _, path := range manifests {
+		dir := filepath.Dir(path)
+		if claimed[dir] || isUnderAny(dir, plainDirs) {
+			continue
+		}
+		pkg, ok := readPackageJSON(path)
+		if !ok {
+			continue
+		}
+		if patterns := nodeWorkspacePatterns(dir, pkg); len(patterns) > 0 {
+			for _, member := range analyzeNodeWorkspace(result, start, dir, pkg, patterns) {
+				claimed[member] = true
 			}
+			claimed[dir] = true
+			continue
+		}
+		// Обычный пакет: вложенные package.json (примеры, фикстуры) не считаем отдельными модулями
+		result.Modules = append(result.Modules, newNodeModule(path, pkg, nil, detectNodePackageManager(dir, pkg)))
+		plainDirs = append(plainDirs, dir)
+	}
+}
 
-			type packageJSON struct {
-				Name            string            `json:"name"`
-				Scripts         map[string]string `json:"scripts"`
-				Dependencies    map[string]string `json:"dependencies"`
-				DevDependencies map[string]string `json:"devDependencies"`
-				Engines         map[string]string `json:"engines"`
-			}
+func readPackageJSON(path string) (packageJSON, bool) {
+	var pkg packageJSON
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return pkg, false
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return pkg, false
+	}
+	return pkg, true
+}
 
-			var pkg packageJSON
-			if err := json.Unmarshal(content, &pkg); err != nil {
-				return nil
-			}
+// newNodeModule описывает пакет; root — корневой package.json workspace-а (engines, typescript), может быть nil.
+func newNodeModule(path string, pkg packageJSON, root *packageJSON, pm BuildTool) *ProjectModule {
+	module := &ProjectModule{
+		Name:         filepath.Base(filepath.Dir(path)),
+		ModulePath:   path,
+		Language:     LanguageJavaScript,
+		BuildTool:    pm,
+		BuildCommand: "npm install && npm run build",
+		TestCommand:  "npm test",
+		BuilderImage: "node:18-alpine",
+		RuntimeImage: "node:18-alpine",
+		ArtifactPath: "dist",
+		AppPort:      "3000",
+	}
+	switch pm {
+	case BuildToolPnpm:
+		module.BuildCommand = "pnpm install --frozen-lockfile && pnpm run build"
+		module.TestCommand = "pnpm test"
+	case BuildToolYarn:
+		module.BuildCommand = "yarn install --frozen-lockfile && yarn build"
+		module.TestCommand = "yarn test"
+	}
 
-			module := &ProjectModule{
-				Name:         filepath.Base(filepath.Dir(path)),
-				ModulePath:   path,
-				Language:     LanguageJavaScript,
-				BuildTool:    BuildToolNpm,
-				BuildCommand: "npm install && npm run build",
-				TestCommand:  "npm test",
-				BuilderImage: "node:18-alpine",
-				RuntimeImage: "node:18-alpine",
-				ArtifactPath: "dist",
-				AppPort:      "3000",
-			}
+	if pkg.Name != "" {
+		module.Name = pkg.Name
+	}
+	if _, ok := pkg.DevDependencies["typescript"]; ok {
+		module.Language = LanguageTypeScript
+	} else if root != nil {
+		if _, ok := root.DevDependencies["typescript"]; ok {
+			module.Language = LanguageTypeScript
+		}
+	}
 
-			if pkg.Name != "" {
-				module.Name = pkg.Name
-			}
-			if _, ok := pkg.DevDependencies["typescript"]; ok {
-				module.Language = LanguageTypeScript
-			}
+	module.LanguageVersion = "20"
+	if v, ok := pkg.Engines["node"]; ok && v != "" {
+		module.VersionConstraint = strings.TrimSpace(v)
+	} else if root != nil && root.Engines["node"] != "" {
+		module.VersionConstraint = strings.TrimSpace(root.Engines["node"])
+	}
+	if v := normalizeNodeVersion(module.VersionConstraint); v != "" {
+		module.LanguageVersion = v
+	}
 
-			if v, ok := pkg.Engines["node"]; ok && v != "" {
-				module.LanguageVersion = normalizeNodeVersion(v)
-			} else {
-				module.LanguageVersion = "20"
-			}
+	module.Framework, module.FrameworkVersion = detectNodeFramework(pkg)
+	if framework, outputDir, ok := detectStaticSite(filepath.Dir(path), pkg); ok {
+		// Статический фронтенд: собирается в outputDir и раздаётся nginx на 8080
+		module.StaticSite = true
+		module.ArtifactPath = outputDir
+		module.AppPort = "8080"
+		module.RuntimeImage = "nginxinc/nginx-unprivileged:1.27-alpine"
+		module.StartCommand = ""
+		if module.Framework == "" {
+			module.Framework = framework
+		}
+	} else {
+		applyNodeFrameworkRuntime(module, filepath.Dir(path))
+	}
+	for dep := range pkg.Dependencies {
+		module.Dependencies = append(module.Dependencies, dep)
+	}
+	sort.Strings(module.Dependencies)
+	return module
+}
 
-			checkFrameworks := func(deps map[string]string) {
-				for dep, ver := range deps {
-					if strings.Contains(dep, "express") {
-						module.Framework = "Express"
-						module.FrameworkVersion = ver
-					} else if strings.Contains(dep, "nestjs") {
-						module.Framework = "NestJS"
-						module.FrameworkVersion = ver
-					} else if strings.Contains(dep, "next") {
-						module.Framework = "Next.js"
-						module.FrameworkVersion = ver
-					} else if strings.Contains(dep, "react") && module.Framework == "" {
-						module.Framework = "React"
-						module.FrameworkVersion = ver
-					} else if strings.Contains(dep, "vue") && module.Framework == "" {
-						module.Framework = "Vue"
-						module.FrameworkVersion = ver
-					}
-				}
-			}
-			checkFrameworks(pkg.Dependencies)
-			result.Modules = append(result.Modules, module)
-			return filepath.SkipDir
+// nodeFrameworkPackages — точные имена пакетов фреймворков в порядке приоритета:
+// NestJS и Next.js тянут за собой express/react, поэтому проверяются раньше.
+var nodeFrameworkPackages = []struct {
+	pkg       string
+	framework string
+	dev       bool // искать и в devDependencies
+}{
+	{"@nestjs/core", "NestJS", false},
+	{"next", "Next.js", true},
+	{"nuxt", "Nuxt", true},
+	{"nuxt3", "Nuxt", true},
+	{"express", "Express", false},
+	{"react", "React", false},
+	{"vue", "Vue", false},
+}
+
+// detectNodeFramework ищет фреймворк по точному имени пакета (не подстроке: "nextra" — не Next.js).
+// Next.js и Nuxt нередко лежат в devDependencies, библиотеки же держат react/vue там как peer — их не считаем.
+func detectNodeFramework(pkg packageJSON) (string, string) {
+	for _, f := range nodeFrameworkPackages {
+		if v, ok := pkg.Dependencies[f.pkg]; ok {
+			return f.framework, v
 		}
-		return nil
-	})
+		if v, ok := pkg.DevDependencies[f.pkg]; ok && f.dev {
+			return f.framework, v
+		}
+	}
+	return "", ""
+}
+
+// detectNodePackageManager: поле packageManager, затем lock-файлы; по умолчанию npm.
+func detectNodePackageManager(dir string, pkg packageJSON) BuildTool {
+	switch {
+	case strings.HasPrefix(pkg.PackageManager, "pnpm@"):
+		return BuildToolPnpm
+	case strings.HasPrefix(pkg.PackageManager, "yarn@"):
+		return BuildToolYarn
+	case strings.HasPrefix(pkg.PackageManager, "npm@"):
+		return BuildToolNpm
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")), fileExists(filepath.Join(dir, "pnpm-workspace.yaml")):
+		return BuildToolPnpm
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		return BuildToolYarn
+	}
+	return BuildToolNpm
+}
+
+func isUnderAny(dir string, parents []string) bool {
+	for _, p := range parents {
+		if rel, err := filepath.Rel(p, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
 }
 
 func normalizeNodeVersion(raw string) string {
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	nextStandaloneRe  = regexp.MustCompile(`\boutput\s*:\s*['"]standalone['"]`)
+	nextTracingRootRe = regexp.MustCompile(`\boutputFileTracingRoot\b`)
+)
+
+// applyNodeFrameworkRuntime задаёт артефакт сборки, команду запуска и порт серверных фреймворков:
+// Next.js (.next/standalone), NestJS (dist/main.js после nest build), Nuxt (Nitro, .output/server).
+func applyNodeFrameworkRuntime(module *ProjectModule, dir string) {
+	switch module.Framework {
+	case "Next.js":
+		module.AppPort = "3000"
+		module.ArtifactPath = ".next"
+		module.StartCommand = "npx next start"
+		if nextStandaloneRe.MatchString(readFirstConfig(dir, "next.config.js", "next.config.mjs", "next.config.ts")) {
+			// output: 'standalone' — трассировка зависимостей, в образ идёт только server.js и нужные node_modules
+			module.ArtifactPath = ".next/standalone"
+			module.StartCommand = "node server.js"
+		}
+	case "NestJS":
+		module.AppPort = "3000"
+		module.ArtifactPath = "dist"
+		module.StartCommand = "node dist/" + nestEntryFile(dir) + ".js"
+	case "Nuxt":
+		module.AppPort = "3000"
+		module.ArtifactPath = ".output"
+		module.StartCommand = "node .output/server/index.mjs"
+	}
+}
+
+// nextStandaloneTracesRoot: outputFileTracingRoot в next.config — в монорепозитории standalone-сборка
+// повторяет структуру каталогов от корня трассировки, и server.js лежит в <пакет>/server.js.
+func nextStandaloneTracesRoot(dir string) bool {
+	return nextTracingRootRe.MatchString(readFirstConfig(dir, "next.config.js", "next.config.mjs", "next.config.ts"))
+}
+
+// nestEntryFile читает entryFile из nest-cli.json (по умолчанию main).
+func nestEntryFile(dir string) string {
+	var cli struct {
+		EntryFile string `json:"entryFile"`
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "nest-cli.json"))
+	if err != nil || json.Unmarshal(content, &cli) != nil || cli.EntryFile == "" {
+		return "main"
+	}
+	return strings.TrimSuffix(cli.EntryFile, ".js")
+}
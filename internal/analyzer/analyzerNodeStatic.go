@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	nextExportRe   = regexp.MustCompile(`\boutput\s*:\s*['"]export['"]`)
+	viteOutDirRe   = regexp.MustCompile(`\boutDir\s*:\s*['"]([^'"]+)['"]`)
+	vueOutputDirRe = regexp.MustCompile(`\boutputDir\s*:\s*['"]([^'"]+)['"]`)
+	sveltePagesRe  = regexp.MustCompile(`\bpages\s*:\s*['"]([^'"]+)['"]`)
+)
+
+// detectStaticSite определяет фронтенд, который собирается в набор статических файлов
+// и раздаётся веб-сервером, а не запускается через node. Возвращает фреймворк и каталог сборки
+// относительно пакета; ok=false — приложению нужен node-рантайм.
+func detectStaticSite(dir string, pkg packageJSON) (framework, outputDir string, ok bool) {
+	has := func(dep string) bool {
+		_, inDeps := pkg.Dependencies[dep]
+		_, inDev := pkg.DevDependencies[dep]
+		return inDeps || inDev
+	}
+
+	switch {
+	case has("next"):
+		// Next.js статичен только с output: 'export' — иначе нужен next start
+		conf := readFirstConfig(dir, "next.config.js", "next.config.mjs", "next.config.ts")
+		if !nextExportRe.MatchString(conf) {
+			return "", "", false
+		}
+		return "Next.js", "out", true
+	case has("@sveltejs/kit"):
+		if !has("@sveltejs/adapter-static") {
+			return "", "", false
+		}
+		out := "build"
+		if m := sveltePagesRe.FindStringSubmatch(readFirstConfig(dir, "svelte.config.js", "svelte.config.mjs", "svelte.config.ts")); m != nil {
+			out = m[1]
+		}
+		return "SvelteKit", out, true
+	case has("nuxt"), has("@remix-run/node"), has("astro") && has("@astrojs/node"):
+		// SSR-фреймворки поверх Vite
+		return "", "", false
+	case has("@angular/core") && fileExists(filepath.Join(dir, "angular.json")):
+		return "Angular", angularOutputPath(filepath.Join(dir, "angular.json")), true
+	case has("@vue/cli-service"):
+		out := "dist"
+		if m := vueOutputDirRe.FindStringSubmatch(readFirstConfig(dir, "vue.config.js", "vue.config.cjs", "vue.config.mjs", "vue.config.ts")); m != nil {
+			out = m[1]
+		}
+		return "Vue", out, true
+	case has("react-scripts"):
+		return "React", "build", true
+	case has("vite"):
+		// Vite с серверным фреймворком — это бэкенд с фронтендом внутри, не статический сайт
+		for _, server := range []string{"express", "fastify", "koa", "@nestjs/core", "@hono/node-server"} {
+			if has(server) {
+				return "", "", false
+			}
+		}
+		out := "dist"
+		if m := viteOutDirRe.FindStringSubmatch(readFirstConfig(dir, "vite.config.ts", "vite.config.js", "vite.config.mjs", "vite.config.mts")); m != nil {
+			out = m[1]
+		}
+		switch {
+		case has("react"):
+			framework = "React"
+		case has("vue"):
+			framework = "Vue"
+		case has("svelte"):
+			framework = "Svelte"
+		default:
+			framework = "Vite"
+		}
+		return framework, out, true
+	}
+	return "", "", false
+}
+
+// angularOutputPath читает outputPath сборки первого приложения из angular.json.
+// Сборщик application (Angular 17+) кладёт файлы браузера в <outputPath>/browser.
+func angularOutputPath(path string) string {
+	var workspace struct {
+		DefaultProject string `json:"defaultProject"`
+		Projects       map[string]struct {
+			ProjectType string `json:"projectType"`
+			Architect   struct {
+				Build struct {
+					Builder string `json:"builder"`
+					Options struct {
+						OutputPath json.RawMessage `json:"outputPath"`
+					} `json:"options"`
+				} `json:"build"`
+			} `json:"architect"`
+		} `json:"projects"`
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(content, &workspace) != nil || len(workspace.Projects) == 0 {
+		return "dist"
+	}
+
+	name := workspace.DefaultProject
+	if _, ok := workspace.Projects[name]; !ok {
+		var names []string
+		for n, p := range workspace.Projects {
+			if p.ProjectType == "" || p.ProjectType == "application" {
+				names = append(names, n)
+			}
+		}
+		if len(names) == 0 {
+			return "dist"
+		}
+		sort.Strings(names)
+		name = names[0]
+	}
+	build := workspace.Projects[name].Architect.Build
+
+	out := "dist/" + name
+	var str string
+	var obj struct {
+		Base    string  `json:"base"`
+		Browser *string `json:"browser"`
+	}
+	switch {
+	case json.Unmarshal(build.Options.OutputPath, &str) == nil && str != "":
+		out = str
+	case json.Unmarshal(build.Options.OutputPath, &obj) == nil && obj.Base != "":
+		out = obj.Base
+		if obj.Browser != nil {
+			// browser: "" — файлы прямо в base
+			if *obj.Browser == "" {
+				return strings.TrimSuffix(out, "/")
+			}
+			return strings.TrimSuffix(out, "/") + "/" + *obj.Browser
+		}
+	}
+	out = strings.TrimSuffix(out, "/")
+	if strings.HasSuffix(build.Builder, ":application") {
+		out += "/browser"
+	}
+	return out
+}
+
+// readFirstConfig возвращает содержимое первого существующего конфига из списка.
+func readFirstConfig(dir string, names ...string) string {
+	for _, name := range names {
+		if content, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return string(content)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// nodeWorkspacePatterns — glob-ы пакетов из "workspaces" в package.json или из pnpm-workspace.yaml.
+func nodeWorkspacePatterns(dir string, pkg packageJSON) []string {
+	var patterns []string
+	if len(pkg.Workspaces) > 0 {
+		if err := json.Unmarshal(pkg.Workspaces, &patterns); err != nil {
+			var obj struct {
+				Packages []string `json:"packages"`
+			}
+			if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+				patterns = obj.Packages
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		patterns = pnpmWorkspacePatterns(filepath.Join(dir, "pnpm-workspace.yaml"))
+	}
+	return patterns
+}
+
+// pnpmWorkspacePatterns читает список packages: из pnpm-workspace.yaml (без полноценного YAML-парсера).
+func pnpmWorkspacePatterns(path string) []string {
+	var patterns []string
+	inPackages := false
+	forEachLine(path, func(_ int, text string) {
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			return
+		}
+		if !strings.HasPrefix(text, " ") && !strings.HasPrefix(text, "-") {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			return
+		}
+		if inPackages && strings.HasPrefix(trimmed, "-") {
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			item = strings.Trim(stripInlineComment(item), `"' `)
+			if item != "" {
+				patterns = append(patterns, item)
+			}
+		}
+	})
+	return patterns
+}
+
+// analyzeNodeWorkspace перечисляет пакеты workspace-а, строит граф зависимостей между ними
+// и добавляет в результат деплоящиеся пакеты. Возвращает каталоги всех пакетов.
+func analyzeNodeWorkspace(result *ProjectAnalysisResult, start, root string, rootPkg packageJSON, patterns []string) []string {
+	members := expandNodeWorkspaces(root, patterns)
+	pm := detectNodePackageManager(root, rootPkg)
+	buildRoot := relDir(start, root)
+
+	graph := BuildGraph{BuildTool: pm, Root: buildRoot}
+	switch {
+	case fileExists(filepath.Join(root, "turbo.json")):
+		graph.Orchestrator = "turbo"
+	case fileExists(filepath.Join(root, "nx.json")):
+		graph.Orchestrator = "nx"
+	}
+
+	type member struct {
+		dir string
+		pkg packageJSON
+	}
+	var pkgs []member
+	names := make(map[string]bool)
+	for _, dir := range members {
+		pkg, ok := readPackageJSON(filepath.Join(dir, "package.json"))
+		if !ok {
+			continue
+		}
+		if pkg.Name == "" {
+			pkg.Name = filepath.Base(dir)
+		}
+		pkgs = append(pkgs, member{dir: dir, pkg: pkg})
+		names[pkg.Name] = true
+	}
+
+	var modules []*ProjectModule
+	for _, m := range pkgs {
+		module := newNodeModule(filepath.Join(m.dir, "package.json"), m.pkg, &rootPkg, pm)
+		node := BuildGraphNode{
+			Name:      m.pkg.Name,
+			Path:      relDir(root, m.dir),
+			Packaging: "package",
+			Framework: module.Framework,
+			Runnable:  isDeployableNodePackage(m.dir, m.pkg, module),
+			Scripts:   sortedKeys(m.pkg.Scripts),
+		}
+		for _, deps := range []map[string]string{m.pkg.Dependencies, m.pkg.DevDependencies} {
+			for dep := range deps {
+				if names[dep] && dep != m.pkg.Name && !containsString(node.DependsOn, dep) {
+					node.DependsOn = append(node.DependsOn, dep)
+				}
+			}
+		}
+		sort.Strings(node.DependsOn)
+		graph.Nodes = append(graph.Nodes, node)
+
+		if !node.Runnable {
+			continue
+		}
+		module.BuildRoot = buildRoot
+		module.ReactorModule = node.Path
+		if module.ArtifactPath == ".next/standalone" && nextStandaloneTracesRoot(m.dir) {
+			module.StartCommand = "node " + node.Path + "/server.js"
+		}
+		module.BuildCommand = nodeWorkspaceRun(pm, graph.Orchestrator, m.pkg.Name, "build")
+		module.TestCommand = nodeWorkspaceRun(pm, graph.Orchestrator, m.pkg.Name, "test")
+		if _, ok := m.pkg.Scripts["start"]; ok && !module.StaticSite && module.StartCommand == "" {
+			module.StartCommand = nodeWorkspaceRun(pm, "", m.pkg.Name, "start")
+		}
+		modules = append(modules, module)
+	}
+	result.BuildGraphs = append(result.BuildGraphs, graph)
+
+	if len(modules) == 0 {
+		// Деплоящихся пакетов нет (набор библиотек) — собираем workspace целиком от корня
+		module := newNodeModule(filepath.Join(root, "package.json"), rootPkg, nil, pm)
+		module.BuildRoot = buildRoot
+		modules = append(modules, module)
+	}
+	result.Modules = append(result.Modules, modules...)
+
+	var dirs []string
+	for _, m := range pkgs {
+		dirs = append(dirs, m.dir)
+	}
+	return dirs
+}
+
+// isDeployableNodePackage: свой Dockerfile, start-скрипт, серверный фреймворк или статический фронтенд.
+// Пакеты-библиотеки (только build/test/lint) не деплоятся.
+func isDeployableNodePackage(dir string, pkg packageJSON, module *ProjectModule) bool {
+	if fileExists(filepath.Join(dir, "Dockerfile")) || module.StaticSite {
+		return true
+	}
+	if _, ok := pkg.Scripts["start"]; ok {
+		return true
+	}
+	return nodeServerFrameworks[module.Framework]
+}
+
+// nodeWorkspaceRun — команда запуска скрипта одного пакета с учётом менеджера и оркестратора.
+func nodeWorkspaceRun(pm BuildTool, orchestrator, name, script string) string {
+	switch orchestrator {
+	case "turbo":
+		return fmt.Sprintf("npx turbo run %s --filter=%s", script, name)
+	case "nx":
+		return fmt.Sprintf("npx nx run %s:%s", name, script)
+	}
+	switch pm {
+	case BuildToolPnpm:
+		return fmt.Sprintf("pnpm --filter %s run %s", name, script)
+	case BuildToolYarn:
+		return fmt.Sprintf("yarn workspace %s run %s", name, script)
+	}
+	return fmt.Sprintf("npm run %s -w %s", script, name)
+}
+
+// expandNodeWorkspaces раскрывает glob-ы ("apps/*", "packages/**", "!**/test/**") в каталоги с package.json.
+func expandNodeWorkspaces(root string, patterns []string) []string {
+	var include, exclude []string
+	for _, p := range patterns {
+		p = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(p)), "./"), "/")
+		if strings.HasPrefix(p, "!") {
+			exclude = append(exclude, strings.TrimPrefix(p, "!"))
+		} else if p != "" {
+			include = append(include, p)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && (shouldSkipDir(d.Name()) || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		rel := relDir(root, path)
+		if strings.Count(rel, "/") > 4 {
+			return filepath.SkipDir
+		}
+		if rel == "." || seen[path] || !fileExists(filepath.Join(path, "package.json")) {
+			return nil
+		}
+		if matchAnyWorkspaceGlob(include, rel) && !matchAnyWorkspaceGlob(exclude, rel) {
+			seen[path] = true
+			out = append(out, path)
+		}
+		return nil
+	})
+	return out
+}
+
+func matchAnyWorkspaceGlob(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchWorkspaceGlob(strings.Split(p, "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWorkspaceGlob сопоставляет сегменты пути с glob-ом; "**" — любое число сегментов.
+func matchWorkspaceGlob(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchWorkspaceGlob(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchWorkspaceGlob(pattern[1:], path[1:])
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func AnalyzePHPModule(result *ProjectAnalysisResult, start string) {
+	targetFile := "composer.json"
+
+	_ = filepath.WalkDir(start, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		if !d.IsDir() && d.Name() == targetFile {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+
+			type composerJSON struct {
+				Name       string            `json:"name"`
+				Type       string            `json:"type"`
+				Require    map[string]string `json:"require"`
+				RequireDev map[string]string `json:"require-dev"`
+			}
+
+			var composer composerJSON
+			if err := json.Unmarshal(content, &composer); err != nil {
+				return nil
+			}
+
+			module := &ProjectModule{
+				Name:            filepath.Base(filepath.Dir(path)),
+				ModulePath:      path,
+				Language:        LanguagePHP,
+				LanguageVersion: "8.2",
+				BuildTool:       BuildToolComposer,
+				BuildCommand:    "composer install --no-dev --optimize-autoloader",
+				TestCommand:     "vendor/bin/phpunit",
+				ArtifactPath:    ".",
+				AppPort:         "8080",
+			}
+
+			if composer.Name != "" {
+				parts := strings.Split(composer.Name, "/")
+				module.Name = parts[len(parts)-1]
+			}
+			if v, ok := composer.Require["php"]; ok {
+				if norm := normalizePHPVersion(v); norm != "" {
+					module.LanguageVersion = norm
+				}
+			}
+			module.BuilderImage = "composer:2"
+			module.RuntimeImage = "php:" + module.LanguageVersion + "-fpm-alpine"
+
+			// Laravel ставит laravel/framework, Symfony — symfony/framework-bundle
+			if v, ok := composer.Require["laravel/framework"]; ok {
+				module.Framework = "Laravel"
+				module.FrameworkVersion = v
+			} else if v, ok := composer.Require["symfony/framework-bundle"]; ok {
+				module.Framework = "Symfony"
+				module.FrameworkVersion = v
+			}
+			if module.Framework == "Symfony" {
+				module.TestCommand = "bin/phpunit"
+			}
+
+			for dep := range composer.Require {
+				if dep != "php" && !strings.HasPrefix(dep, "ext-") {
+					module.Dependencies = append(module.Dependencies, dep)
+				}
+			}
+			sort.Strings(module.Dependencies)
+
+			result.Modules = append(result.Modules, module)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// normalizePHPVersion приводит ограничение вида "^8.1|^8.2", ">=7.4", "~8.0" к "X.Y".
+// Берётся наибольшая из перечисленных версий — она же обычно самая поддерживаемая.
+func normalizePHPVersion(raw string) string {
+	re := regexp.MustCompile(`(\d+)\.(\d+)`)
+	best := ""
+	for _, m := range re.FindAllStringSubmatch(raw, -1) {
+		candidate := m[1] + "." + m[2]
+		if best == "" || compareMajorMinor(candidate, best) > 0 {
+			best = candidate
+		}
+	}
+	if best != "" {
+		return best
+	}
+	// "^8" / ">=8"
+	if m := regexp.MustCompile(`\d+`).FindString(raw); m != "" {
+		return m + ".0"
+	}
+	return ""
+}
+
+// compareMajorMinor сравнивает версии "X.Y" численно.
+func compareMajorMinor(a, b string) int {
+	pa := strings.SplitN(a, ".", 2)
+	pb := strings.SplitN(b, ".", 2)
+	for i := 0; i < 2; i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if d := atoiDigits(x) - atoiDigits(y); d != 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func atoiDigits(s string) int {
+	v := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			break
+		}
+		v = v*10 + int(r-'0')
+	}
+	return v
+}
@@ -0,0 +1,331 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Уровни доверия к найденному порту
+const (
+	confidenceExplicit = 0.9 // EXPOSE, server.port — порт задан явно
+	confidenceLiteral  = 0.8 // литерал в вызове listen/run
+	confidenceEnvFall  = 0.6 // значение по умолчанию для переменной окружения PORT
+	confidenceImplicit = 0.4 // поведение фреймворка по умолчанию (gin.Run(), manage.py)
+	confidenceDefault  = 0.1 // языковой дефолт без каких-либо улик
+)
+
+// maxPortScanFileSize — крупные файлы (бандлы, сгенерированный код) не сканируем
+const maxPortScanFileSize = 512 * 1024
+
+var (
+	jsListenRe      = regexp.MustCompile(`\.listen\(\s*(\d{2,5})\b`)
+	jsEnvPortRe     = regexp.MustCompile(`process\.env\.PORT\s*(?:\|\||\?\?)\s*['"]?(\d{2,5})`)
+	pyRunPortRe     = regexp.MustCompile(`(uvicorn\.run|\.run)\(.*\bport\s*=\s*(\d{2,5})`)
+	pyBindRe        = regexp.MustCompile(`(?:--bind|-b|bind\s*=)\s*['"]?[\w.]*:(\d{2,5})`)
+	runserverRe     = regexp.MustCompile(`runserver\s+(?:[\w.]+:)?(\d{2,5})`)
+	dockerExposeRe  = regexp.MustCompile(`(?i)^\s*EXPOSE\s+(\d{2,5})`)
+	springPortRe    = regexp.MustCompile(`^\s*server\.port\s*[=:]\s*(?:\$\{[A-Z_]+:)?(\d{2,5})`)
+	yamlPortRe      = regexp.MustCompile(`^\s+port\s*:\s*['"]?(?:\$\{[A-Z_]+:)?(\d{2,5})`)
+	goAddrLiteralRe = regexp.MustCompile(`^[\w.\[\]]*:(\d{2,5})$`)
+)
+
+// InferModulePorts собирает улики о порту приложения для каждого модуля
+// и выставляет AppPort по самой надёжной из них.
+func InferModulePorts(result *ProjectAnalysisResult, root string) {
+	for _, m := range result.Modules {
+		dir := filepath.Dir(m.ModulePath)
+		var evidence []PortEvidence
+
+		switch m.Language {
+		case LanguageGo:
+			evidence = append(evidence, scanGoPorts(dir, root, m.Framework == "Gin")...)
+		case LanguageJavaScript, LanguageTypeScript:
+			if m.StaticSite {
+				// порт dev-сервера не важен: в образе статику раздаёт nginx
+				evidence = append(evidence, PortEvidence{Port: "8080", Confidence: confidenceImplicit, Source: "default", Reason: "nginx static hosting"})
+				break
+			}
+			evidence = append(evidence, scanTextPorts(dir, root, []string{".js", ".mjs", ".cjs", ".ts"}, jsPortRules)...)
+		case LanguagePython:
+			evidence = append(evidence, scanTextPorts(dir, root, []string{".py", ".cfg", ".toml", ".ini"}, pyPortRules)...)
+			evidence = append(evidence, scanNamedFilePorts(dir, root, []string{"Procfile", "Makefile"}, pyPortRules)...)
+			if fileExists(filepath.Join(dir, "manage.py")) {
+				evidence = append(evidence, PortEvidence{Port: "8000", Confidence: confidenceImplicit, Source: relSource(root, filepath.Join(dir, "manage.py"), 0), Reason: "django runserver default"})
+			}
+		case LanguageJava:
+			evidence = append(evidence, scanSpringPorts(dir, root)...)
+		}
+		evidence = append(evidence, scanDockerfilePorts(dir, root, m.DockerfilePath)...)
+
+		if len(evidence) == 0 && m.AppPort != "" {
+			evidence = append(evidence, PortEvidence{Port: m.AppPort, Confidence: confidenceDefault, Source: "default", Reason: "language default"})
+		}
+
+		sort.SliceStable(evidence, func(i, j int) bool { return evidence[i].Confidence > evidence[j].Confidence })
+		m.PortEvidence = evidence
+		if len(evidence) > 0 {
+			m.AppPort = evidence[0].Port
+		}
+	}
+}
+
+// scanGoPorts ищет через AST: http.ListenAndServe(":9090"), r.Run(":8080"), e.Start, app.Listen,
+// а также http.Server{Addr: ":8080"}. gin.Run() без аргументов слушает 8080 —
+// учитываем это только для Gin-модулей, иначе под Run() попадёт любой exec.Cmd.
+func scanGoPorts(dir, root string, isGin bool) []PortEvidence {
+	var out []PortEvidence
+	fset := token.NewFileSet()
+
+	walkSourceFiles(dir, func(path string) {
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				switch sel.Sel.Name {
+				case "ListenAndServe", "ListenAndServeTLS", "Run", "RunTLS", "Listen", "Start":
+				default:
+					return true
+				}
+				line := fset.Position(node.Pos()).Line
+				if len(node.Args) == 0 {
+					if sel.Sel.Name == "Run" && isGin {
+						out = append(out, PortEvidence{Port: "8080", Confidence: confidenceImplicit, Source: relSource(root, path, line), Reason: "gin Run() default"})
+					}
+					return true
+				}
+				addr := node.Args[0]
+				// net.Listen("tcp", ":8080"), tls.Listen("tcp", addr, cfg): адрес — второй аргумент
+				if sel.Sel.Name == "Listen" && len(node.Args) >= 2 {
+					addr = node.Args[1]
+				}
+				if port := goAddrPort(addr); port != "" {
+					out = append(out, PortEvidence{Port: port, Confidence: confidenceLiteral, Source: relSource(root, path, line), Reason: sel.Sel.Name})
+				}
+			case *ast.KeyValueExpr:
+				if key, ok := node.Key.(*ast.Ident); ok && key.Name == "Addr" {
+					if port := goAddrPort(node.Value); port != "" {
+						out = append(out, PortEvidence{Port: port, Confidence: confidenceLiteral, Source: relSource(root, path, fset.Position(node.Pos()).Line), Reason: "http.Server Addr"})
+					}
+				}
+			}
+			return true
+		})
+	})
+	return out
+}
+
+// goAddrPort достаёт порт из строкового литерала ":9090" / "0.0.0.0:9090".
+func goAddrPort(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	if m := goAddrLiteralRe.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+type portRule struct {
+	re         *regexp.Regexp
+	group      int
+	confidence float64
+	reason     string
+}
+
+var jsPortRules = []portRule{
+	{re: jsListenRe, group: 1, confidence: confidenceLiteral, reason: "app.listen"},
+	{re: jsEnvPortRe, group: 1, confidence: confidenceEnvFall, reason: "process.env.PORT default"},
+}
+
+var pyPortRules = []portRule{
+	{re: pyRunPortRe, group: 2, confidence: confidenceLiteral, reason: "run(port=...)"},
+	{re: pyBindRe, group: 1, confidence: confidenceExplicit, reason: "gunicorn/uvicorn bind"},
+	{re: runserverRe, group: 1, confidence: confidenceExplicit, reason: "manage.py runserver"},
+}
+
+func scanTextPorts(dir, root string, exts []string, rules []portRule) []PortEvidence {
+	var out []PortEvidence
+	walkSourceFiles(dir, func(path string) {
+		if !containsString(exts, filepath.Ext(path)) {
+			return
+		}
+		out = append(out, matchPortRules(path, root, rules)...)
+	})
+	return out
+}
+
+func scanNamedFilePorts(dir, root string, names []string, rules []portRule) []PortEvidence {
+	var out []PortEvidence
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if fileExists(p) {
+			out = append(out, matchPortRules(p, root, rules)...)
+		}
+	}
+	return out
+}
+
+func matchPortRules(path, root string, rules []portRule) []PortEvidence {
+	var out []PortEvidence
+	forEachLine(path, func(line int, text string) {
+		for _, r := range rules {
+			if m := r.re.FindStringSubmatch(text); m != nil {
+				out = append(out, PortEvidence{Port: m[r.group], Confidence: r.confidence, Source: relSource(root, path, line), Reason: r.reason})
+			}
+		}
+	})
+	return out
+}
+
+// scanSpringPorts читает server.port из application*.properties и server: port: из application*.yml.
+func scanSpringPorts(dir, root string) []PortEvidence {
+	var out []PortEvidence
+	resources := filepath.Join(dir, "src", "main", "resources")
+	entries, err := os.ReadDir(resources)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "application") {
+			continue
+		}
+		path := filepath.Join(resources, name)
+		switch filepath.Ext(name) {
+		case ".properties":
+			forEachLine(path, func(line int, text string) {
+				if m := springPortRe.FindStringSubmatch(text); m != nil {
+					out = append(out, PortEvidence{Port: m[1], Confidence: springConfidence(name), Source: relSource(root, path, line), Reason: "server.port"})
+				}
+			})
+		case ".yml", ".yaml":
+			inServer := false
+			forEachLine(path, func(line int, text string) {
+				trimmed := strings.TrimSpace(text)
+				if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+					return
+				}
+				if !strings.HasPrefix(text, " ") && !strings.HasPrefix(text, "\t") {
+					inServer = strings.HasPrefix(trimmed, "server:")
+					return
+				}
+				if inServer {
+					if m := yamlPortRe.FindStringSubmatch(text); m != nil {
+						out = append(out, PortEvidence{Port: m[1], Confidence: springConfidence(name), Source: relSource(root, path, line), Reason: "server.port"})
+					}
+				}
+			})
+		}
+	}
+	return out
+}
+
+// springConfidence: профильные конфиги (application-dev.yml) весят меньше основного.
+func springConfidence(name string) float64 {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if base == "application" {
+		return confidenceExplicit
+	}
+	return confidenceEnvFall
+}
+
+// scanDockerfilePorts — EXPOSE из Dockerfile модуля; если анализатор его не нашёл — из Dockerfile* в каталоге модуля.
+func scanDockerfilePorts(dir, root, dockerfile string) []PortEvidence {
+	if dockerfile != "" {
+		return scanDockerfileExpose(dockerfile, root)
+	}
+	var out []PortEvidence
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(strings.ToLower(e.Name()), "dockerfile") {
+			continue
+		}
+		out = append(out, scanDockerfileExpose(filepath.Join(dir, e.Name()), root)...)
+	}
+	return out
+}
+
+func scanDockerfileExpose(path, root string) []PortEvidence {
+	var out []PortEvidence
+	forEachLine(path, func(line int, text string) {
+		if m := dockerExposeRe.FindStringSubmatch(text); m != nil {
+			out = append(out, PortEvidence{Port: m[1], Confidence: confidenceExplicit, Source: relSource(root, path, line), Reason: "Dockerfile EXPOSE"})
+		}
+	})
+	return out
+}
+
+// walkSourceFiles обходит исходники модуля, пропуская служебные каталоги и крупные файлы.
+func walkSourceFiles(dir string, fn func(path string)) {
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && (shouldSkipDir(d.Name()) || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxPortScanFileSize {
+			return nil
+		}
+		fn(path)
+		return nil
+	})
+}
+
+func forEachLine(path string, fn func(line int, text string)) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPortScanFileSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fn(line, scanner.Text())
+	}
+}
+
+// relSource форматирует "путь:строка" относительно корня репозитория.
+func relSource(root, path string, line int) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	if line <= 0 {
+		return rel
+	}
+	return fmt.Sprintf("%s:%d", rel, line)
+}
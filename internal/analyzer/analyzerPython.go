@@ -3,9 +3,10 @@ package analyzer
 import (
 	"bufio"
 	"bytes"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -28,41 +29,51 @@ func AnalyzePythonModule(result *ProjectAnalysisResult, start string) {
 		}
 
 		if !d.IsDir() && containsString(targetFiles, d.Name()) {
-			content, err := ioutil.ReadFile(path)
+			content, err := os.ReadFile(path)
 			if err != nil {
 				return nil
 			}
+			dir := filepath.Dir(path)
+			manifest := parsePythonManifest(d.Name(), content)
 
 			module := &ProjectModule{
-				Name:         filepath.Base(filepath.Dir(path)),
+				Name:         filepath.Base(dir),
 				ModulePath:   path,
 				Language:     LanguagePython,
-				BuilderImage: "python:3.11-slim",
-				RuntimeImage: "python:3.11-slim",
+				BuilderImage: "python:3.12-slim",
+				RuntimeImage: "python:3.12-slim",
 				ArtifactPath: ".",
 				AppPort:      "8000",
 			}
 
-			switch d.Name() {
-			case "requirements.txt":
+			switch {
+			case d.Name() == "requirements.txt":
 				module.BuildTool = BuildToolPip
 				module.BuildCommand = "pip install -r requirements.txt"
 				module.TestCommand = "pytest"
-			case "Pipfile":
+			case d.Name() == "Pipfile":
 				module.BuildTool = BuildToolPipenv
 				module.BuildCommand = "pipenv install"
 				module.TestCommand = "pipenv run pytest"
-			case "pyproject.toml":
+			case manifest.poetry || fileExists(filepath.Join(dir, "poetry.lock")):
 				module.BuildTool = BuildToolPoetry
 				module.BuildCommand = "poetry install"
 				module.TestCommand = "poetry run pytest"
+			default:
+				// PEP 621 без Poetry (setuptools, hatch, pdm-backend): ставится обычным pip
+				module.BuildTool = BuildToolPip
+				module.BuildCommand = "pip install ."
+				module.TestCommand = "pytest"
 			}
 
-			fw, ver := detectPythonFramework(content)
-			if fw != "" {
-				module.Framework = fw
-				module.FrameworkVersion = ver
+			module.VersionConstraint = detectPythonVersionConstraint(dir, manifest)
+			if v := resolvePythonVersion(module.VersionConstraint); v != "" {
+				module.LanguageVersion = v
+				module.BuilderImage = "python:" + v + "-slim"
+				module.RuntimeImage = "python:" + v + "-slim"
 			}
+			module.Framework, module.FrameworkVersion = detectPythonFramework(manifest)
+			module.Dependencies = manifest.deps
 
 			result.Modules = append(result.Modules, module)
 			return filepath.SkipDir
@@ -71,35 +82,125 @@ func AnalyzePythonModule(result *ProjectAnalysisResult, start string) {
 	})
 }
 
-func detectPythonFramework(content []byte) (string, string) {
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	frameworks := []string{"django", "flask", "fastapi", "tornado", "pyramid", "starlette", "sanic"}
+// pythonFrameworks — пакеты фреймворков в порядке приоритета и их отображаемые имена.
+var pythonFrameworks = []struct{ pkg, name string }{
+	{"django", "Django"},
+	{"fastapi", "FastAPI"},
+	{"flask", "Flask"},
+	{"sanic", "Sanic"},
+	{"tornado", "Tornado"},
+	{"pyramid", "Pyramid"},
+	{"starlette", "Starlette"},
+}
+
+// detectPythonFramework ищет фреймворк среди зависимостей; версия — первое число из ограничения ("^0.110" -> "0.110").
+func detectPythonFramework(manifest pythonManifest) (string, string) {
+	for _, fw := range pythonFrameworks {
+		if spec, ok := manifest.specs[fw.pkg]; ok {
+			return fw.name, pyVersionNumberRe.FindString(spec)
+		}
+	}
+	return "", ""
+}
+
+// pythonManifest — зависимости и требования к интерпретатору из одного манифеста.
+type pythonManifest struct {
+	deps           []string          // имена пакетов в нижнем регистре, в порядке объявления
+	specs          map[string]string // имя -> ограничение версии ("==4.2", "^0.110", "")
+	requiresPython string            // requires-python, [tool.poetry.dependencies] python, [requires] python_version
+	poetry         bool              // есть [tool.poetry]
+}
+
+func (pm *pythonManifest) add(name, spec string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "python" {
+		return
+	}
+	if _, ok := pm.specs[name]; ok {
+		return
+	}
+	pm.specs[name] = strings.TrimSpace(spec)
+	pm.deps = append(pm.deps, name)
+}
+
+// addRequirement разбирает строку PEP 508: "fastapi[all]>=0.110; python_version>'3.8'".
+func (pm *pythonManifest) addRequirement(line string) {
+	if m := pyRequirementRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+		pm.add(m[1], m[2])
+	}
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(strings.ToLower(scanner.Text()))
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
+// addTOMLDependencies добавляет таблицу зависимостей Poetry/Pipenv: name = "^1.0" или name = {version = "^1.0", extras = [...]}.
+func (pm *pythonManifest) addTOMLDependencies(table map[string]any) {
+	names := make([]string, 0, len(table))
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch v := table[name].(type) {
+		case string:
+			pm.add(name, v)
+		case map[string]any:
+			spec, _ := v["version"].(string)
+			pm.add(name, spec)
 		}
+	}
+}
 
-		for _, fw := range frameworks {
-			if line == fw || strings.HasPrefix(line, fw+"=") || strings.HasPrefix(line, fw+">") || strings.HasPrefix(line, fw+"<") {
-				version := ""
-				if strings.Contains(line, "==") {
-					parts := strings.Split(line, "==")
-					if len(parts) > 1 {
-						version = strings.TrimSpace(parts[1])
+// parsePythonManifest разбирает requirements.txt построчно, а Pipfile и pyproject.toml — как TOML:
+// [packages]/[requires] Pipfile, [project] (PEP 621) и [tool.poetry] в pyproject.toml.
+func parsePythonManifest(fileName string, content []byte) pythonManifest {
+	pm := pythonManifest{specs: make(map[string]string)}
+	switch fileName {
+	case "requirements.txt":
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+				continue
+			}
+			pm.addRequirement(line)
+		}
+	case "Pipfile":
+		// частично разобранный документ тоже полезен — ошибку разбора не считаем фатальной
+		doc, _ := parseTOML(content)
+		pm.addTOMLDependencies(tomlTableAt(doc, "packages"))
+		pm.requiresPython = firstNonEmptyString(tomlString(doc, "requires", "python_full_version"), tomlString(doc, "requires", "python_version"))
+	case "pyproject.toml":
+		doc, _ := parseTOML(content)
+		if project := tomlTableAt(doc, "project"); project != nil {
+			if deps, ok := project["dependencies"].([]any); ok {
+				for _, d := range deps {
+					if s, ok := d.(string); ok {
+						pm.addRequirement(s)
 					}
 				}
-				return capitalize(fw), version
 			}
+			pm.requiresPython = tomlString(doc, "project", "requires-python")
+		}
+		if poetry := tomlTableAt(doc, "tool", "poetry"); poetry != nil {
+			pm.poetry = true
+			deps := tomlTableAt(doc, "tool", "poetry", "dependencies")
+			if pm.requiresPython == "" {
+				pm.requiresPython, _ = deps["python"].(string)
+			}
+			pm.addTOMLDependencies(deps)
 		}
 	}
-	return "", ""
+	return pm
 }
 
-func capitalize(s string) string {
-	if len(s) == 0 {
-		return ""
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
 	}
-	return strings.ToUpper(s[:1]) + s[1:]
+	return ""
 }
+
+var (
+	pyRequirementRe   = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.\-]*)\s*(?:\[[^\]]*\])?\s*([^;#@]*)`)
+	pyVersionNumberRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+)
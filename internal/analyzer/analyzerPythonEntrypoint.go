@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	djangoSettingsRe = regexp.MustCompile(`DJANGO_SETTINGS_MODULE['"]?\s*,\s*['"]([\w.]+)['"]`)
+	djangoWSGIRe     = regexp.MustCompile(`^\s*WSGI_APPLICATION\s*=\s*['"]([\w.]+)['"]`)
+	djangoASGIRe     = regexp.MustCompile(`^\s*ASGI_APPLICATION\s*=\s*['"]([\w.]+)['"]`)
+	pyAppAssignRe    = regexp.MustCompile(`^(\w+)\s*(?::\s*\w+\s*)?=\s*(FastAPI|Starlette|Flask|Quart|Sanic)\(`)
+	pyAppFactoryRe   = regexp.MustCompile(`^def\s+(create_app|make_app|app_factory)\s*\(`)
+	procfileWebRe    = regexp.MustCompile(`^web\s*:\s*(.+)$`)
+	procfileAppRe    = regexp.MustCompile(`(?:gunicorn|uvicorn|hypercorn|daphne)\b.*?\s([\w.]+:[\w()]+)`)
+)
+
+// asgiAppClasses — классы приложений, которым нужен ASGI-сервер.
+var asgiAppClasses = map[string]bool{"FastAPI": true, "Starlette": true, "Quart": true, "Sanic": true}
+
+// ResolvePythonEntrypoints ищет, как запускать Python-модуль: web-процесс из Procfile,
+// Django (manage.py + WSGI_APPLICATION/ASGI_APPLICATION из settings), место создания
+// FastAPI()/Flask(__name__), [project.scripts]/[tool.poetry.scripts]. Заполняет WSGIModule/ASGIModule
+// и StartCommand с gunicorn/uvicorn. Вызывается после InferModulePorts, чтобы в команду попал найденный порт.
+func ResolvePythonEntrypoints(result *ProjectAnalysisResult) {
+	for _, m := range result.Modules {
+		if m.Language != LanguagePython {
+			continue
+		}
+		dir := filepath.Dir(m.ModulePath)
+
+		if cmd, app := procfileWebCommand(dir); cmd != "" {
+			// Procfile — явное указание автора, берём как есть
+			m.StartCommand = cmd
+			switch {
+			case app == "":
+			case strings.Contains(cmd, "uvicorn"), strings.Contains(cmd, "UvicornWorker"), strings.Contains(cmd, "daphne"), strings.Contains(cmd, "hypercorn"):
+				m.ASGIModule = app
+			default:
+				m.WSGIModule = app
+			}
+			continue
+		}
+
+		switch {
+		case fileExists(filepath.Join(dir, "manage.py")):
+			m.WSGIModule, m.ASGIModule = djangoApplication(dir)
+		default:
+			if app, chdir, asgi := findPythonApp(dir); app != "" {
+				if asgi {
+					m.ASGIModule = app
+				} else {
+					m.WSGIModule = app
+				}
+				m.StartCommand = pythonServerCommand(m, chdir)
+				continue
+			}
+		}
+		if m.WSGIModule != "" || m.ASGIModule != "" {
+			m.StartCommand = pythonServerCommand(m, "")
+			continue
+		}
+
+		if script := pythonConsoleScript(dir); script != "" {
+			m.StartCommand = script
+			continue
+		}
+		for _, name := range []string{"main.py", "app.py", "server.py", "run.py"} {
+			if fileExists(filepath.Join(dir, name)) {
+				m.StartCommand = "python " + name
+				break
+			}
+		}
+	}
+}
+
+// pythonServerCommand собирает команду сервера приложений.
+// WSGI (Django, Flask): gunicorn с sync-воркерами и потоками — вызовы к БД блокирующие.
+// ASGI (FastAPI, Starlette): воркеры uvicorn — под gunicorn, если он в зависимостях, иначе uvicorn --workers.
+func pythonServerCommand(m *ProjectModule, chdir string) string {
+	port := m.AppPort
+	if port == "" {
+		port = "8000"
+	}
+	if m.ASGIModule != "" {
+		switch {
+		case containsString(m.Dependencies, "gunicorn"):
+			cmd := "gunicorn " + m.ASGIModule + " -k uvicorn.workers.UvicornWorker --bind 0.0.0.0:" + port + " --workers 2 --timeout 60 --access-logfile -"
+			if chdir != "" {
+				cmd += " --chdir " + chdir
+			}
+			return cmd
+		case containsString(m.Dependencies, "daphne") && !containsString(m.Dependencies, "uvicorn"):
+			return "daphne -b 0.0.0.0 -p " + port + " " + m.ASGIModule
+		}
+		cmd := "uvicorn " + m.ASGIModule + " --host 0.0.0.0 --port " + port + " --workers 2 --proxy-headers"
+		if chdir != "" {
+			cmd += " --app-dir " + chdir
+		}
+		return cmd
+	}
+	cmd := "gunicorn " + m.WSGIModule + " --bind 0.0.0.0:" + port + " --workers 3 --threads 2 --timeout 60 --access-logfile -"
+	if chdir != "" {
+		cmd += " --chdir " + chdir
+	}
+	return cmd
+}
+
+// procfileWebCommand возвращает команду процесса web из Procfile и модуль приложения в ней.
+func procfileWebCommand(dir string) (cmd, app string) {
+	forEachLine(filepath.Join(dir, "Procfile"), func(_ int, text string) {
+		if cmd != "" {
+			return
+		}
+		if m := procfileWebRe.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+			cmd = strings.TrimSpace(m[1])
+		}
+	})
+	if m := procfileAppRe.FindStringSubmatch(cmd); m != nil {
+		app = m[1]
+	}
+	return cmd, app
+}
+
+// djangoApplication: DJANGO_SETTINGS_MODULE из manage.py -> settings.py (или пакет settings/) ->
+// WSGI_APPLICATION = "proj.wsgi.application" -> "proj.wsgi:application".
+// ASGI заполняется только при ASGI_APPLICATION (Channels) в настройках.
+func djangoApplication(dir string) (wsgi, asgi string) {
+	settings := ""
+	forEachLine(filepath.Join(dir, "manage.py"), func(_ int, text string) {
+		if m := djangoSettingsRe.FindStringSubmatch(text); m != nil && settings == "" {
+			settings = m[1]
+		}
+	})
+
+	var files []string
+	if settings != "" {
+		base := filepath.Join(append([]string{dir}, strings.Split(settings, ".")...)...)
+		files = append(files, base+".py", filepath.Join(base, "__init__.py"), filepath.Join(base, "base.py"))
+		// пакет settings/ с base/production: WSGI_APPLICATION обычно в base.py
+		files = append(files, filepath.Join(filepath.Dir(base), "settings", "base.py"))
+	}
+	for _, f := range files {
+		forEachLine(f, func(_ int, text string) {
+			if m := djangoWSGIRe.FindStringSubmatch(text); m != nil && wsgi == "" {
+				wsgi = dottedToAppRef(m[1])
+			}
+			if m := djangoASGIRe.FindStringSubmatch(text); m != nil && asgi == "" {
+				asgi = dottedToAppRef(m[1])
+			}
+		})
+	}
+
+	if wsgi == "" && settings != "" {
+		// WSGI_APPLICATION не задан — Django берёт <проект>.wsgi.application
+		project := strings.Split(settings, ".")[0]
+		if fileExists(filepath.Join(dir, project, "wsgi.py")) {
+			wsgi = project + ".wsgi:application"
+		}
+	}
+	return wsgi, asgi
+}
+
+// dottedToAppRef: "proj.wsgi.application" -> "proj.wsgi:application".
+func dottedToAppRef(dotted string) string {
+	i := strings.LastIndex(dotted, ".")
+	if i < 0 {
+		return dotted
+	}
+	return dotted[:i] + ":" + dotted[i+1:]
+}
+
+// findPythonApp ищет модуль, где создаётся приложение (app = FastAPI(), app = Flask(__name__))
+// или объявлена фабрика create_app(). Ближе к корню и с типичным именем (main, app, wsgi, asgi) — приоритетнее.
+// Для src-layout возвращает chdir = "src", т.к. пакет импортируется относительно него.
+func findPythonApp(dir string) (app, chdir string, asgi bool) {
+	type candidate struct {
+		ref   string
+		asgi  bool
+		score int
+	}
+	var found []candidate
+	walkSourceFiles(dir, func(path string) {
+		if !strings.HasSuffix(path, ".py") {
+			return
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.Count(rel, "/") > 3 || isPythonTestFile(rel) {
+			return
+		}
+		var ref string
+		var isASGI bool
+		forEachLine(path, func(_ int, text string) {
+			if ref != "" {
+				return
+			}
+			if m := pyAppAssignRe.FindStringSubmatch(text); m != nil {
+				ref, isASGI = m[1], asgiAppClasses[m[2]]
+			} else if m := pyAppFactoryRe.FindStringSubmatch(text); m != nil {
+				ref = m[1] + "()"
+			}
+		})
+		if ref == "" {
+			return
+		}
+		module := strings.TrimSuffix(rel, ".py")
+		module = strings.TrimSuffix(module, "/__init__")
+		score := strings.Count(module, "/") * 10
+		switch filepath.Base(module) {
+		case "main", "app", "wsgi", "asgi", "server", "api":
+		default:
+			score += 5
+		}
+		if strings.HasSuffix(ref, "()") {
+			// фабрика хуже готового объекта: app = create_app() в wsgi.py найдётся отдельно
+			score += 3
+		}
+		found = append(found, candidate{ref: strings.ReplaceAll(module, "/", ".") + ":" + ref, asgi: isASGI, score: score})
+	})
+	if len(found) == 0 {
+		return "", "", false
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score < found[j].score
+		}
+		return found[i].ref < found[j].ref
+	})
+	best := found[0]
+	if strings.HasPrefix(best.ref, "src.") {
+		return strings.TrimPrefix(best.ref, "src."), "src", best.asgi
+	}
+	return best.ref, "", best.asgi
+}
+
+func isPythonTestFile(rel string) bool {
+	base := filepath.Base(rel)
+	return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") || base == "conftest.py" ||
+		strings.HasPrefix(rel, "tests/") || strings.Contains(rel, "/tests/") || strings.HasPrefix(rel, "docs/")
+}
+
+// pythonConsoleScript возвращает первую (по имени) консольную команду из
+// [project.scripts] или [tool.poetry.scripts] — после установки пакета она есть в PATH.
+func pythonConsoleScript(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	if err != nil {
+		return ""
+	}
+	doc, err := parseTOML(content)
+	if err != nil {
+		return ""
+	}
+	for _, path := range [][]string{{"project", "scripts"}, {"tool", "poetry", "scripts"}} {
+		scripts := tomlTableAt(doc, path...)
+		var names []string
+		for name, v := range scripts {
+			if _, ok := v.(string); ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return names[0]
+		}
+	}
+	return ""
+}
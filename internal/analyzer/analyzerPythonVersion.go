@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pythonImagePreference — минорные версии с образами python:X.Y-slim в порядке предпочтения:
+// сначала версия по умолчанию, затем ближайшие к ней. Берётся первая, удовлетворяющая ограничению.
+var pythonImagePreference = []string{"3.12", "3.11", "3.13", "3.10", "3.9", "3.8"}
+
+var (
+	pyConstraintRe = regexp.MustCompile(`^(===|==|!=|~=|>=|<=|>|<|\^|~|=)?\s*v?(\d+)(?:\.(\d+|\*))?(?:\.(\d+|\*))?`)
+	runtimeTxtRe   = regexp.MustCompile(`^python-(\d+\.\d+)`)
+)
+
+// detectPythonVersionConstraint возвращает требование к версии Python по убыванию точности:
+// .python-version (pyenv/uv), runtime.txt (Heroku), Pipfile [requires], requires-python / tool.poetry python.
+func detectPythonVersionConstraint(dir string, manifest pythonManifest) string {
+	if content, err := os.ReadFile(filepath.Join(dir, ".python-version")); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") && pyVersionNumberRe.MatchString(line) {
+				// "3.11.4", "3.12" или "pypy3.10-7.3.12" — берём номер версии
+				return "==" + pyVersionNumberRe.FindString(line)
+			}
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, "runtime.txt")); err == nil {
+		if m := runtimeTxtRe.FindStringSubmatch(strings.TrimSpace(string(content))); m != nil {
+			return "==" + m[1]
+		}
+	}
+	if manifest.requiresPython != "" {
+		return manifest.requiresPython
+	}
+	for _, name := range []string{"Pipfile", "pyproject.toml"} {
+		if content, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			if c := parsePythonManifest(name, content).requiresPython; c != "" {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
+// resolvePythonVersion подбирает минорную версию с образом под ограничение PEP 440 или Poetry:
+// ">=3.9,<3.12", "^3.11", "~=3.10", "3.11.*", "3.11" (Pipfile), ">=3.8 || ^3.12". Пустая строка — не удалось.
+func resolvePythonVersion(constraint string) string {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return ""
+	}
+	for _, candidate := range pythonImagePreference {
+		minor, _ := strconv.Atoi(strings.TrimPrefix(candidate, "3."))
+		for _, alternative := range strings.Split(constraint, "||") {
+			if pythonMinorSatisfies(3, minor, alternative) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// pythonMinorSatisfies проверяет, есть ли у X.Y хотя бы один патч-релиз, подходящий под все условия.
+func pythonMinorSatisfies(major, minor int, constraint string) bool {
+	var clauses []string
+	for _, part := range strings.Split(constraint, ",") {
+		// Poetry допускает условия через пробел: ">=3.9 <3.12"
+		clauses = append(clauses, splitKeepingOperators(part)...)
+	}
+	cand := [2]int{major, minor}
+	for _, clause := range clauses {
+		clause = strings.TrimSpace(clause)
+		if clause == "" || clause == "*" {
+			continue
+		}
+		m := pyConstraintRe.FindStringSubmatch(clause)
+		if m == nil {
+			return false
+		}
+		op := m[1]
+		vMajor, _ := strconv.Atoi(m[2])
+		vMinor, hasMinor := 0, m[3] != "" && m[3] != "*"
+		if hasMinor {
+			vMinor, _ = strconv.Atoi(m[3])
+		}
+		vPatch, hasPatch := 0, m[4] != "" && m[4] != "*"
+		if hasPatch {
+			vPatch, _ = strconv.Atoi(m[4])
+		}
+		v := [2]int{vMajor, vMinor}
+		cmp := compareMinor(cand, v)
+
+		ok := true
+		switch op {
+		case ">=", ">":
+			// ">3.8" пропускает 3.8.1 — на уровне минорной версии это то же, что ">="
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case "<":
+			ok = cmp < 0 || (cmp == 0 && hasPatch && vPatch > 0)
+		case "!=":
+			ok = hasPatch || cmp != 0
+		case "~=":
+			// ~=3.10 -> >=3.10,<4; ~=3.10.2 -> >=3.10.2,<3.11
+			if hasPatch {
+				ok = cmp == 0
+			} else {
+				ok = cmp >= 0 && major == vMajor
+			}
+		case "^":
+			ok = cmp >= 0 && major == vMajor
+		case "~":
+			ok = cmp == 0 || (!hasMinor && major == vMajor)
+		default: // "==", "===", "=" или голая версия
+			if !hasMinor {
+				ok = major == vMajor
+			} else {
+				ok = cmp == 0
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// splitKeepingOperators делит ">=3.9 <3.12" на условия по пробелу перед оператором.
+func splitKeepingOperators(constraint string) []string {
+	var out []string
+	fields := strings.Fields(constraint)
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		// оператор отдельно от версии: ">= 3.9"
+		if strings.Trim(f, "<>=!~^") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func compareMinor(a, b [2]int) int {
+	switch {
+	case a[0] != b[0]:
+		return a[0] - b[0]
+	default:
+		return a[1] - b[1]
+	}
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	gemLineRe  = regexp.MustCompile(`^\s*gem\s+['"]([^'"]+)['"](?:\s*,\s*['"]([^'"]+)['"])?`)
+	rubyLineRe = regexp.MustCompile(`^\s*ruby\s+['"]([^'"]+)['"]`)
+)
+
+func AnalyzeRubyModule(result *ProjectAnalysisResult, start string) {
+	targetFile := "Gemfile"
+
+	_ = filepath.WalkDir(start, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		if !d.IsDir() && d.Name() == targetFile {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			dir := filepath.Dir(path)
+
+			module := &ProjectModule{
+				Name:            filepath.Base(dir),
+				ModulePath:      path,
+				Language:        LanguageRuby,
+				LanguageVersion: "3.3",
+				BuildTool:       BuildToolBundler,
+				BuildCommand:    "bundle install",
+				TestCommand:     "bundle exec rspec",
+				ArtifactPath:    ".",
+				AppPort:         "3000",
+			}
+
+			gems := map[string]string{}
+			for _, line := range strings.Split(string(content), "\n") {
+				if m := gemLineRe.FindStringSubmatch(line); m != nil {
+					gems[m[1]] = m[2]
+					module.Dependencies = append(module.Dependencies, m[1])
+				} else if m := rubyLineRe.FindStringSubmatch(line); m != nil {
+					if v := normalizeRubyVersion(m[1]); v != "" {
+						module.LanguageVersion = v
+					}
+				}
+			}
+
+			// .ruby-version приоритетнее директивы ruby в Gemfile
+			if b, err := os.ReadFile(filepath.Join(dir, ".ruby-version")); err == nil {
+				if v := normalizeRubyVersion(string(b)); v != "" {
+					module.LanguageVersion = v
+				}
+			}
+
+			_, hasPuma := gems["puma"]
+			if v, ok := gems["rails"]; ok {
+				module.Framework = "Rails"
+				module.FrameworkVersion = v
+				module.StartCommand = "bundle exec rails server -b 0.0.0.0 -p 3000"
+				if fileExists(filepath.Join(dir, "config", "puma.rb")) {
+					module.StartCommand = "bundle exec puma -C config/puma.rb"
+				}
+			} else if v, ok := gems["sinatra"]; ok {
+				module.Framework = "Sinatra"
+				module.FrameworkVersion = v
+				module.AppPort = "4567"
+				if hasPuma {
+					module.StartCommand = "bundle exec puma -b tcp://0.0.0.0:4567 config.ru"
+				} else {
+					module.StartCommand = "bundle exec rackup -o 0.0.0.0 -p 4567"
+				}
+			}
+
+			if _, ok := gems["rspec"]; !ok {
+				if _, ok := gems["rspec-rails"]; !ok {
+					// Без rspec в Rails по умолчанию используется minitest
+					if module.Framework == "Rails" {
+						module.TestCommand = "bundle exec rails test"
+					} else {
+						module.TestCommand = "bundle exec rake test"
+					}
+				}
+			}
+
+			module.BuilderImage = "ruby:" + module.LanguageVersion + "-slim"
+			module.RuntimeImage = "ruby:" + module.LanguageVersion + "-slim"
+
+			result.Modules = append(result.Modules, module)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// normalizeRubyVersion приводит "ruby-3.2.2", "3.2.2", "~> 3.1" к "X.Y".
+func normalizeRubyVersion(raw string) string {
+	m := regexp.MustCompile(`(\d+)\.(\d+)`).FindStringSubmatch(raw)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "." + m[2]
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+)
+
+var gradleJdkTagRe = regexp.MustCompile(`-jdk\d+`)
+
+// runtimeForLanguage — рантайм каталога образов для языка модуля.
+var runtimeForLanguage = map[Language]string{
+	LanguageGo:         catalog.RuntimeGo,
+	LanguageJavaScript: catalog.RuntimeNode,
+	LanguageTypeScript: catalog.RuntimeNode,
+	LanguagePython:     catalog.RuntimePython,
+	LanguageJava:       catalog.RuntimeTemurin,
+}
+
+// PinRuntimeVersions выбирает по офлайн-каталогу образов линию рантайма, заявленную VersionConstraint модуля
+// (go 1.22.3 -> 1.22, ^3.11 -> 3.11), и проставляет её в LanguageVersion и образы сборки/запуска:
+// тег линии фиксирует рантайм в пределах major.minor, патч-версии подтягиваются внутри неё.
+// Если выбранная линия уже без поддержки или каталог давно не обновлялся — добавляет предупреждение.
+// Без каталога версии остаются такими, какими их определили анализаторы.
+func PinRuntimeVersions(result *ProjectAnalysisResult) {
+	c, err := catalog.Load()
+	if err != nil {
+		result.addWarning(fmt.Sprintf("image catalog unavailable (%v): runtime versions are taken from manifests as is", err))
+		return
+	}
+	now := time.Now()
+	if c.Stale(now) {
+		result.addWarning(fmt.Sprintf("image catalog was last updated on %s; run `catalog update <file>` to refresh tags and EOL dates", c.UpdatedAt))
+	}
+
+	for _, m := range result.Modules {
+		runtime, ok := runtimeForLanguage[m.Language]
+		if !ok {
+			continue
+		}
+		release, ok := c.Resolve(runtime, m.VersionConstraint)
+		if !ok {
+			result.addWarning(fmt.Sprintf("%s: no %s release in the catalog satisfies %q, keeping %s", m.Name, runtime, m.VersionConstraint, m.LanguageVersion))
+			continue
+		}
+		m.LanguageVersion = release.Version
+		applyRuntimeImages(m, c.Repository(runtime), release.Version)
+		if release.EOLAt(now) {
+			result.addWarning(fmt.Sprintf("%s: %s %s reached end of life on %s", m.Name, runtime, release.Version, release.EOL))
+		}
+	}
+}
+
+// applyRuntimeImages переписывает теги образов модуля на выбранную линию рантайма.
+func applyRuntimeImages(m *ProjectModule, repository, v string) {
+	switch m.Language {
+	case LanguageGo:
+		m.BuilderImage = repository + ":" + v + "-alpine"
+	case LanguageJavaScript, LanguageTypeScript:
+		m.BuilderImage = repository + ":" + v + "-alpine"
+		if !m.StaticSite {
+			m.RuntimeImage = m.BuilderImage
+		}
+	case LanguagePython:
+		m.BuilderImage = repository + ":" + v + "-slim"
+		m.RuntimeImage = m.BuilderImage
+	case LanguageJava:
+		switch m.BuildTool {
+		case BuildToolGradle:
+			m.BuilderImage = gradleJdkTagRe.ReplaceAllString(m.BuilderImage, "-jdk"+v)
+		default:
+			m.BuilderImage = "maven:3.9-" + repository + "-" + v
+		}
+		m.RuntimeImage = repository + ":" + v + "-jre"
+	}
+}
+
+func (par *ProjectAnalysisResult) addWarning(msg string) {
+	if !containsString(par.Warnings, msg) {
+		par.Warnings = append(par.Warnings, msg)
+	}
+}
+
+// goMinorVersion: "1.22.3" -> "1.22", "1.21rc2" -> "1.21".
+func goMinorVersion(v string) string {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(v), "go"), ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	minor := parts[1]
+	for i := 0; i < len(minor); i++ {
+		if minor[i] < '0' || minor[i] > '9' {
+			minor = minor[:i]
+			break
+		}
+	}
+	return parts[0] + "." + minor
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Виды внешних сервисов, которые поднимаются рядом с приложением в docker-compose
+const (
+	ServicePostgres = "postgres"
+	ServiceMySQL    = "mysql"
+	ServiceRedis    = "redis"
+	ServiceKafka    = "kafka"
+	ServiceRabbitMQ = "rabbitmq"
+	ServiceMongo    = "mongo"
+)
+
+// serviceRule сопоставляет клиентские библиотеки с сервисом.
+// exact — имя пакета целиком (pg, redis), contains — подстрока пути/координат (jackc/pgx, org.postgresql:postgresql).
+type serviceRule struct {
+	service  string
+	exact    []string
+	contains []string
+}
+
+var serviceRules = []serviceRule{
+	{
+		service:  ServicePostgres,
+		exact:    []string{"pg", "pg-promise", "postgres", "psycopg", "psycopg2", "psycopg2-binary", "asyncpg", "sqlalchemy"},
+		contains: []string{"jackc/pgx", "lib/pq", "gorm.io/driver/postgres", "org.postgresql:postgresql", "io.r2dbc:r2dbc-postgresql"},
+	},
+	{
+		service:  ServiceMySQL,
+		exact:    []string{"mysql", "mysql2", "mysqlclient", "pymysql", "aiomysql"},
+		contains: []string{"go-sql-driver/mysql", "gorm.io/driver/mysql", "mysql:mysql-connector-java", "com.mysql:mysql-connector-j", "org.mariadb.jdbc"},
+	},
+	{
+		service:  ServiceRedis,
+		exact:    []string{"redis", "ioredis", "django-redis", "aioredis", "predis/predis"},
+		contains: []string{"go-redis/redis", "redis/go-redis", "gomodule/redigo", "spring-boot-starter-data-redis", "redis.clients:jedis", "io.lettuce"},
+	},
+	{
+		service:  ServiceKafka,
+		exact:    []string{"kafkajs", "kafka-python", "aiokafka", "confluent-kafka", "node-rdkafka"},
+		contains: []string{"segmentio/kafka-go", "confluentinc/confluent-kafka-go", "ibm/sarama", "shopify/sarama", "org.springframework.kafka", "org.apache.kafka"},
+	},
+	{
+		service:  ServiceRabbitMQ,
+		exact:    []string{"amqplib", "amqp-connection-manager", "pika", "aio-pika", "kombu"},
+		contains: []string{"streadway/amqp", "rabbitmq/amqp091-go", "spring-boot-starter-amqp", "com.rabbitmq"},
+	},
+	{
+		service:  ServiceMongo,
+		exact:    []string{"mongodb", "mongoose", "pymongo", "motor", "mongoengine"},
+		contains: []string{"go.mongodb.org/mongo-driver", "spring-boot-starter-data-mongodb", "org.mongodb"},
+	},
+}
+
+// DetectBackingServices сопоставляет зависимости модулей с базами, кешами и брокерами.
+// Заполняет ProjectModule.Services и сводный ProjectAnalysisResult.BackingServices.
+func DetectBackingServices(result *ProjectAnalysisResult) {
+	byService := make(map[string]*BackingService)
+
+	for _, m := range result.Modules {
+		found := make(map[string]string) // service -> зависимость-улика
+		hasCelery := false
+		for _, dep := range m.Dependencies {
+			lower := strings.ToLower(dep)
+			if lower == "celery" {
+				hasCelery = true
+			}
+			for _, rule := range serviceRules {
+				if _, ok := found[rule.service]; ok {
+					continue
+				}
+				if matchServiceRule(rule, lower) {
+					found[rule.service] = dep
+				}
+			}
+		}
+		// Celery без явного redis-клиента по умолчанию работает через RabbitMQ
+		if hasCelery {
+			if _, ok := found[ServiceRedis]; !ok {
+				if _, ok := found[ServiceRabbitMQ]; !ok {
+					found[ServiceRabbitMQ] = "celery"
+				}
+			}
+		}
+
+		m.Services = m.Services[:0]
+		for svc, dep := range found {
+			m.Services = append(m.Services, svc)
+			bs, ok := byService[svc]
+			if !ok {
+				bs = &BackingService{Name: svc}
+				byService[svc] = bs
+			}
+			bs.Modules = append(bs.Modules, m.Name)
+			bs.Evidence = append(bs.Evidence, m.Name+": "+dep)
+		}
+		sort.Strings(m.Services)
+	}
+
+	result.BackingServices = result.BackingServices[:0]
+	for _, bs := range byService {
+		result.BackingServices = append(result.BackingServices, *bs)
+	}
+	sort.Slice(result.BackingServices, func(i, j int) bool {
+		return result.BackingServices[i].Name < result.BackingServices[j].Name
+	})
+}
+
+func matchServiceRule(rule serviceRule, dep string) bool {
+	for _, e := range rule.exact {
+		if dep == e {
+			return true
+		}
+	}
+	for _, c := range rule.contains {
+		if strings.Contains(dep, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -16,6 +16,8 @@ const (
 	LanguageJavaScript Language = "javascript"
 	LanguageTypeScript Language = "typescript"
 	LanguageKotlin     Language = "kotlin"
+	LanguagePHP        Language = "php"
+	LanguageRuby       Language = "ruby"
 	LanguageUnknown    Language = "unknown"
 )
 
@@ -31,6 +33,8 @@ const (
 	BuildToolPipenv    BuildTool = "pipenv"
 	BuildToolPoetry    BuildTool = "poetry"
 	BuildToolGoModules BuildTool = "go-modules"
+	BuildToolComposer  BuildTool = "composer"
+	BuildToolBundler   BuildTool = "bundler"
 	BuildToolUnknown   BuildTool = "unknown"
 )
 
@@ -54,21 +58,108 @@ type ProjectModule struct {
 	Dependencies     []string  `json:"dependencies"`
 	BuildCommand     string    `json:"build_command"`
 	TestCommand      string    `json:"test_command"`
+	StartCommand     string    `json:"start_command"`
 	DockerfilePath   string    `json:"dockerfile_path"`
 	BuilderImage     string    `json:"builder_image"`
 	RuntimeImage     string    `json:"runtime_image"`
 	ArtifactPath     string    `json:"artifact_path"`
 	AppPort          string    `json:"app_port"`
+
+	// BuildTargets — исполняемые артефакты модуля (для Go — каталоги с package main).
+	BuildTargets []BuildTarget `json:"build_targets,omitempty"`
+	// PortEvidence — откуда взят AppPort, по убыванию доверия.
+	PortEvidence []PortEvidence `json:"port_evidence,omitempty"`
+	// HealthPath — HTTP-эндпоинт проверки здоровья ("/actuator/health", "/healthz"); пусто — не найден.
+	HealthPath string `json:"health_path,omitempty"`
+	// Services — внешние сервисы (postgres, redis, ...), клиенты которых есть в зависимостях.
+	Services []string `json:"services,omitempty"`
+	// BuildRoot — каталог многомодульной сборки относительно репозитория ("." — корень).
+	BuildRoot string `json:"build_root,omitempty"`
+	// ReactorModule — путь подмодуля относительно BuildRoot (mvn -pl <ReactorModule> -am, подпроект Gradle, пакет workspace).
+	ReactorModule string `json:"reactor_module,omitempty"`
+	// BuildTask — задача Gradle, собирающая исполняемый артефакт (":api:bootJar", "installDist").
+	BuildTask string `json:"build_task,omitempty"`
+	// DockerContext — контекст сборки DockerfilePath: каталог, относительно которого Dockerfile копирует файлы.
+	DockerContext string `json:"docker_context,omitempty"`
+	// StaticSite — фронтенд (Vite, CRA, Angular, ...), собираемый в ArtifactPath и раздаваемый веб-сервером.
+	StaticSite bool `json:"static_site,omitempty"`
+	// WSGIModule/ASGIModule — объект приложения для gunicorn/uvicorn ("proj.wsgi:application", "app.main:app").
+	WSGIModule string `json:"wsgi_module,omitempty"`
+	ASGIModule string `json:"asgi_module,omitempty"`
+	// VersionConstraint — требование к версии рантайма из манифеста как есть (engines.node, requires-python,
+	// директива go). Пусто — проект версию не указал. LanguageVersion — линия, выбранная по каталогу образов.
+	VersionConstraint string `json:"version_constraint,omitempty"`
+}
+
+// BackingService — база/кеш/брокер, нужный одному или нескольким модулям.
+type BackingService struct {
+	Name     string   `json:"name"`     // см. Service* константы
+	Modules  []string `json:"modules"`  // какие модули его используют
+	Evidence []string `json:"evidence"` // "module: dependency"
+}
+
+// EnvVar — переменная окружения, которую читает приложение.
+type EnvVar struct {
+	Name       string   `json:"name"`
+	Default    string   `json:"default,omitempty"`
+	HasDefault bool     `json:"has_default"`
+	Required   bool     `json:"required"` // ни в одном месте нет значения по умолчанию и чтение не через os.LookupEnv
+	Secret     bool     `json:"secret"`   // по имени: *_PASSWORD, *_TOKEN, *_SECRET, ...
+	Modules    []string `json:"modules,omitempty"`
+	Sources    []string `json:"sources"` // "config/config.go:12", ".env.example:3"
+}
+
+// PortEvidence — одна улика о порту приложения.
+type PortEvidence struct {
+	Port       string  `json:"port"`
+	Confidence float64 `json:"confidence"` // 0..1
+	Source     string  `json:"source"`     // "cmd/api/main.go:42" или "default"
+	Reason     string  `json:"reason"`     // что именно найдено: "ListenAndServe", "Dockerfile EXPOSE"
 }
 
+// BuildTarget описывает один собираемый бинарник: имя и путь пакета относительно модуля.
+type BuildTarget struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // "./cmd/api", "."
+}
+
+// BuildGraph — граф модулей многомодульной сборки (Maven reactor, Gradle multi-project, Node workspaces).
+type BuildGraph struct {
+	BuildTool    BuildTool        `json:"build_tool"`
+	Root         string           `json:"root"`                   // каталог корневого pom.xml / settings.gradle / package.json относительно репозитория
+	Orchestrator string           `json:"orchestrator,omitempty"` // turbo, nx
+	Nodes        []BuildGraphNode `json:"nodes"`
+}
+
+// BuildGraphNode — один модуль сборки и его зависимости внутри графа.
+type BuildGraphNode struct {
+	Name      string   `json:"name"` // artifactId для Maven, путь проекта (":api") для Gradle, имя пакета для Node
+	Path      string   `json:"path"` // относительно BuildGraph.Root, "." для корня
+	Packaging string   `json:"packaging"`
+	Framework string   `json:"framework,omitempty"`
+	Runnable  bool     `json:"runnable"` // собирается в исполняемый артефакт / деплоится (spring-boot plugin, start-скрипт, Dockerfile)
+	DependsOn []string `json:"depends_on,omitempty"`
+	Scripts   []string `json:"scripts,omitempty"` // npm-скрипты пакета
+}
+
+// AnalysisSchemaVersion — версия формата ProjectAnalysisResult для внешних потребителей (портал).
+// Мажорная версия меняется при удалении/переименовании полей, минорная — при добавлении.
+const AnalysisSchemaVersion = "1.2"
+
 type ProjectAnalysisResult struct {
+	SchemaVersion        string             `json:"schema_version"`
 	RepositoryName       string             `json:"repository_name"`
-	Languages            map[string]float64 `json:"languages_percent"` // Статистика для "20 баллов"
-	Infrastructure       []string           `json:"infrastructure"`    // Docker, K8s
+	Languages            map[string]float64 `json:"languages_percent"`         // Статистика для "20 баллов"
+	Infrastructure       []string           `json:"infrastructure"`            // Docker, K8s
+	KustomizeFiles       []string           `json:"kustomize_files,omitempty"` // существующие kustomization.yaml относительно репозитория
 	Modules              []*ProjectModule   `json:"modules"`
 	PipelineStrategy     PipelineStrategy   `json:"pipeline_strategy"`
 	MainFramework        string             `json:"main_framework"`
 	MainFrameworkVersion string             `json:"main_framework_version"`
+	BackingServices      []BackingService   `json:"backing_services"`
+	EnvVars              []EnvVar           `json:"env_vars"` // Инвентарь конфигурации через окружение
+	BuildGraphs          []BuildGraph       `json:"build_graphs,omitempty"`
+	Warnings             []string           `json:"warnings,omitempty"` // EOL рантаймов, устаревший каталог образов
 }
 
 func (par *ProjectAnalysisResult) PrintSummary() {
@@ -0,0 +1,383 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML — небольшой разборщик TOML для конфигов сборки (libs.versions.toml, pyproject.toml).
+// Поддерживает таблицы, массивы таблиц, dotted-ключи, строки (в т.ч. многострочные), числа,
+// булевы значения, массивы и inline-таблицы. Даты и прочие скаляры возвращаются строкой.
+func parseTOML(content []byte) (map[string]any, error) {
+	p := &tomlParser{s: strings.ReplaceAll(string(content), "\r\n", "\n")}
+	root := make(map[string]any)
+	current := root
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+		if p.peek() == '[' {
+			arrayTable := strings.HasPrefix(p.s[p.i:], "[[")
+			if arrayTable {
+				p.i += 2
+			} else {
+				p.i++
+			}
+			key, err := p.parseKey()
+			if err != nil {
+				return root, err
+			}
+			p.skipSpaces()
+			closing := "]"
+			if arrayTable {
+				closing = "]]"
+			}
+			if !strings.HasPrefix(p.s[p.i:], closing) {
+				return root, p.errorf("expected %q", closing)
+			}
+			p.i += len(closing)
+			if current, err = tomlTable(root, key, arrayTable); err != nil {
+				return root, err
+			}
+			if err := p.endOfLine(); err != nil {
+				return root, err
+			}
+			continue
+		}
+
+		if err := p.parseKeyValue(current); err != nil {
+			return root, err
+		}
+		if err := p.endOfLine(); err != nil {
+			return root, err
+		}
+	}
+}
+
+// tomlTable возвращает (создавая по пути) таблицу [a.b] или новый элемент [[a.b]].
+func tomlTable(root map[string]any, key []string, arrayTable bool) (map[string]any, error) {
+	cur := root
+	for i, k := range key {
+		last := i == len(key)-1
+		switch v := cur[k].(type) {
+		case nil:
+			next := make(map[string]any)
+			if last && arrayTable {
+				cur[k] = []any{next}
+			} else {
+				cur[k] = next
+			}
+			cur = next
+		case map[string]any:
+			if last && arrayTable {
+				return nil, fmt.Errorf("toml: %s is a table, not an array of tables", strings.Join(key, "."))
+			}
+			cur = v
+		case []any:
+			if last && arrayTable {
+				next := make(map[string]any)
+				cur[k] = append(v, next)
+				cur = next
+				continue
+			}
+			if len(v) == 0 {
+				return nil, fmt.Errorf("toml: empty array at %s", k)
+			}
+			table, ok := v[len(v)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("toml: %s is not a table", k)
+			}
+			cur = table
+		default:
+			return nil, fmt.Errorf("toml: %s is not a table", k)
+		}
+	}
+	return cur, nil
+}
+
+type tomlParser struct {
+	s string
+	i int
+}
+
+func (p *tomlParser) eof() bool  { return p.i >= len(p.s) }
+func (p *tomlParser) peek() byte { return p.s[p.i] }
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.s[:p.i], "\n") + 1
+	return fmt.Errorf("toml line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.i++
+	}
+}
+
+// skipBlank пропускает пробелы, переводы строк и комментарии.
+func (p *tomlParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		case '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipSpaces()
+	if p.eof() {
+		return nil
+	}
+	switch p.peek() {
+	case '\n':
+		p.i++
+		return nil
+	case '#':
+		for !p.eof() && p.peek() != '\n' {
+			p.i++
+		}
+		return nil
+	}
+	return p.errorf("unexpected %q after value", p.peek())
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]any) error {
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpaces()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf("expected '=' after key %s", strings.Join(key, "."))
+	}
+	p.i++
+	p.skipSpaces()
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	for _, k := range key[:len(key)-1] {
+		next, ok := table[k].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			table[k] = next
+		}
+		table = next
+	}
+	table[key[len(key)-1]] = value
+	return nil
+}
+
+// parseKey читает ключ: bare, "quoted" или 'literal' части через точку.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var parts []string
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf("unexpected end of key")
+		}
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, s)
+		default:
+			start := p.i
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.i++
+			}
+			if start == p.i {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			parts = append(parts, p.s[start:p.i])
+		}
+		p.skipSpaces()
+		if p.eof() || p.peek() != '.' {
+			return parts, nil
+		}
+		p.i++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	if p.eof() {
+		return nil, p.errorf("missing value")
+	}
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.i
+	for !p.eof() && !strings.ContainsRune(",]}\n#", rune(p.peek())) {
+		p.i++
+	}
+	raw := strings.TrimSpace(p.s[start:p.i])
+	switch raw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "":
+		return nil, p.errorf("missing value")
+	}
+	clean := strings.ReplaceAll(raw, "_", "")
+	if n, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, nil
+	}
+	return raw, nil
+}
+
+func (p *tomlParser) parseString() (string, error) {
+	quote := p.peek()
+	if strings.HasPrefix(p.s[p.i:], strings.Repeat(string(quote), 3)) {
+		p.i += 3
+		// перевод строки сразу после открывающих кавычек не входит в значение
+		if !p.eof() && p.peek() == '\n' {
+			p.i++
+		}
+		end := strings.Index(p.s[p.i:], strings.Repeat(string(quote), 3))
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		raw := p.s[p.i : p.i+end]
+		p.i += end + 3
+		if quote == '\'' {
+			return raw, nil
+		}
+		return unescapeTOML(raw), nil
+	}
+
+	p.i++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == quote:
+			p.i++
+			if quote == '\'' {
+				return b.String(), nil
+			}
+			return unescapeTOML(b.String()), nil
+		case c == '\n':
+			return "", p.errorf("newline in string")
+		case c == '\\' && quote == '"' && p.i+1 < len(p.s):
+			b.WriteByte(c)
+			b.WriteByte(p.s[p.i+1])
+			p.i += 2
+			continue
+		}
+		b.WriteByte(c)
+		p.i++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func unescapeTOML(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	if u, err := strconv.Unquote(`"` + strings.ReplaceAll(s, "\n", `\n`) + `"`); err == nil {
+		return u
+	}
+	return s
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.i++ // [
+	out := []any{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.i++
+			return out, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ',' {
+			p.i++
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.i++ // {
+	out := make(map[string]any)
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.i++
+			return out, nil
+		}
+		if err := p.parseKeyValue(out); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.eof() && p.peek() == ',' {
+			p.i++
+		}
+	}
+}
+
+// tomlString достаёт строку по пути ключей ("project", "requires-python").
+func tomlString(doc map[string]any, path ...string) string {
+	var cur any = doc
+	for _, k := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return ""
+		}
+		cur = m[k]
+	}
+	s, _ := cur.(string)
+	return s
+}
+
+// tomlTableAt возвращает вложенную таблицу по пути ключей или nil.
+func tomlTableAt(doc map[string]any, path ...string) map[string]any {
+	cur := doc
+	for _, k := range path {
+		next, ok := cur[k].(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
@@ -0,0 +1,189 @@
+// Package catalog — офлайн-каталог официальных образов рантаймов (golang, node, python, temurin):
+// выпущенные версии и даты окончания поддержки. Каталог лежит рядом с шаблонами и обновляется
+// командой "catalog update", сеть для генерации не нужна.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SchemaVersion — версия формата файла каталога.
+const SchemaVersion = 1
+
+// DefaultPath — каталог, поставляемый вместе с шаблонами.
+var DefaultPath = filepath.Join("templates", "catalog", "images.json")
+
+// Имена рантаймов в каталоге
+const (
+	RuntimeGo      = "golang"
+	RuntimeNode    = "node"
+	RuntimePython  = "python"
+	RuntimeTemurin = "temurin"
+)
+
+// StaleAfter — каталог старше этого срока, скорее всего, не знает свежих релизов.
+const StaleAfter = 180 * 24 * time.Hour
+
+type Catalog struct {
+	SchemaVersion int              `json:"schema_version"`
+	UpdatedAt     string           `json:"updated_at"` // YYYY-MM-DD
+	Images        map[string]Image `json:"images"`
+}
+
+type Image struct {
+	Repository string `json:"repository"` // имя образа на Docker Hub
+	// Default — линия для проектов, которые не указали версию; пусто — самая новая LTS (или самая новая).
+	Default  string    `json:"default,omitempty"`
+	Releases []Release `json:"releases"`
+}
+
+// Release — линия версий, под которую есть плавающий тег: golang:1.23, node:20, python:3.12, eclipse-temurin:21.
+type Release struct {
+	Version  string `json:"version"`
+	Released string `json:"released,omitempty"`
+	EOL      string `json:"eol"`
+	LTS      bool   `json:"lts,omitempty"`
+}
+
+// EOLAt сообщает, закончилась ли поддержка к моменту now.
+func (r Release) EOLAt(now time.Time) bool {
+	eol, err := time.Parse(time.DateOnly, r.EOL)
+	return err == nil && !now.Before(eol)
+}
+
+// Load читает каталог из DefaultPath.
+func Load() (*Catalog, error) {
+	return LoadFile(DefaultPath)
+}
+
+func LoadFile(path string) (*Catalog, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+	var c Catalog
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("parse catalog %s: %w", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", path, err)
+	}
+	c.sortReleases()
+	return &c, nil
+}
+
+// Validate проверяет формат: известная версия схемы, даты YYYY-MM-DD, непустые версии без повторов.
+func (c *Catalog) Validate() error {
+	if c.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("unsupported schema_version %d (want %d)", c.SchemaVersion, SchemaVersion)
+	}
+	if _, err := time.Parse(time.DateOnly, c.UpdatedAt); err != nil {
+		return fmt.Errorf("updated_at: %w", err)
+	}
+	if len(c.Images) == 0 {
+		return fmt.Errorf("no images")
+	}
+	for name, img := range c.Images {
+		if img.Repository == "" {
+			return fmt.Errorf("%s: empty repository", name)
+		}
+		if len(img.Releases) == 0 {
+			return fmt.Errorf("%s: no releases", name)
+		}
+		seen := make(map[string]bool)
+		for _, r := range img.Releases {
+			if _, ok := parseVersion(r.Version); !ok {
+				return fmt.Errorf("%s: bad version %q", name, r.Version)
+			}
+			if seen[r.Version] {
+				return fmt.Errorf("%s: duplicate version %s", name, r.Version)
+			}
+			seen[r.Version] = true
+			if _, err := time.Parse(time.DateOnly, r.EOL); err != nil {
+				return fmt.Errorf("%s %s: eol: %w", name, r.Version, err)
+			}
+		}
+		if img.Default != "" && !seen[img.Default] {
+			return fmt.Errorf("%s: default %s is not among releases", name, img.Default)
+		}
+	}
+	return nil
+}
+
+// Import проверяет файл каталога и заменяет им каталог по пути dst.
+func Import(src, dst string) (*Catalog, error) {
+	c, err := LoadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal catalog: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(dst), err)
+	}
+	if err := os.WriteFile(dst, append(raw, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("write catalog: %w", err)
+	}
+	return c, nil
+}
+
+// Repository возвращает имя образа рантайма на Docker Hub.
+func (c *Catalog) Repository(runtime string) string {
+	return c.Images[runtime].Repository
+}
+
+// Stale — каталог давно не обновлялся.
+func (c *Catalog) Stale(now time.Time) bool {
+	updated, err := time.Parse(time.DateOnly, c.UpdatedAt)
+	return err == nil && now.Sub(updated) > StaleAfter
+}
+
+// Resolve выбирает линию рантайма под ограничение (npm semver, PEP 440, Poetry, go.mod): заявленную
+// проектом, то есть самую старую подходящую — "go 1.22.3" и "^3.11" остаются на 1.22 и 3.11, а не уходят
+// на новейшую линию, которую ограничение формально допускает. Без ограничения берётся Default,
+// иначе самая новая LTS-линия, а если LTS нет — самая новая.
+func (c *Catalog) Resolve(runtime, constraint string) (Release, bool) {
+	img, ok := c.Images[runtime]
+	if !ok {
+		return Release{}, false
+	}
+	if isAny(constraint) {
+		for _, r := range img.Releases {
+			if r.Version == img.Default {
+				return r, true
+			}
+		}
+		for _, r := range img.Releases {
+			if r.LTS {
+				return r, true
+			}
+		}
+		return img.Releases[0], true
+	}
+	// линии отсортированы от новой к старой
+	for i := len(img.Releases) - 1; i >= 0; i-- {
+		if Matches(img.Releases[i].Version, constraint) {
+			return img.Releases[i], true
+		}
+	}
+	return Release{}, false
+}
+
+// sortReleases упорядочивает линии от новой к старой.
+func (c *Catalog) sortReleases() {
+	for name, img := range c.Images {
+		sort.SliceStable(img.Releases, func(i, j int) bool {
+			a, _ := parseVersion(img.Releases[i].Version)
+			b, _ := parseVersion(img.Releases[j].Version)
+			return compareVersions(a, b) > 0
+		})
+		c.Images[name] = img
+	}
+}
@@ -0,0 +1,217 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version — числовые компоненты версии; частичная версия ("3.12", "20") обозначает всю линию.
+type version []int
+
+// bound — полуинтервал [lo, hi); hi == nil — без верхней границы.
+type bound struct {
+	lo, hi version
+}
+
+// parseVersion разбирает "1.23", "v20.11.1", "go1.22.3", "3.12.*", "20.x", "3.13.0rc1".
+// Подстановочный компонент (x, *) обрывает версию: "3.12.*" -> [3 12].
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "go")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if s == "" {
+		return nil, false
+	}
+	var v version
+	for _, part := range strings.Split(s, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			return nil, false
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			return nil, false
+		}
+		v = append(v, n)
+		if end < len(part) {
+			// pre-release/build-суффикс: дальше компоненты не читаем
+			break
+		}
+	}
+	return v, len(v) > 0
+}
+
+// compareVersions сравнивает версии, дополняя короткую нулями.
+func compareVersions(a, b version) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// next — первая версия после линии v: 3.12 -> 3.13, 20 -> 21, 1.22.3 -> 1.22.4.
+func (v version) next() version {
+	n := append(version{}, v...)
+	n[len(n)-1]++
+	return n
+}
+
+// prefix — первые n компонентов версии.
+func (v version) prefix(n int) version {
+	if n > len(v) {
+		n = len(v)
+	}
+	return append(version{}, v[:n]...)
+}
+
+func isAny(constraint string) bool {
+	switch strings.ToLower(strings.TrimSpace(constraint)) {
+	case "", "*", "x", "latest", "lts", "lts/*", "node", "stable":
+		return true
+	}
+	return false
+}
+
+// Matches сообщает, есть ли в линии line (например "3.12" или "20") версия, удовлетворяющая ограничению.
+// Поддерживаются операторы npm/PEP 440/Poetry: >= > <= < = == != ~= ^ ~, x-диапазоны (20.x, 3.11.*),
+// диапазоны через дефис (18 - 20), альтернативы через || и условия через запятую или пробел.
+func Matches(line, constraint string) bool {
+	l, ok := parseVersion(line)
+	if !ok {
+		return false
+	}
+	if isAny(constraint) {
+		return true
+	}
+	for _, alt := range strings.Split(constraint, "||") {
+		if matchesAll(l, alt) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAll проверяет, что пересечение линии со всеми условиями альтернативы непусто.
+func matchesAll(line version, alt string) bool {
+	alt = strings.TrimSpace(alt)
+	if isAny(alt) {
+		return true
+	}
+	b := bound{lo: line, hi: line.next()}
+
+	if lo, hi, found := strings.Cut(alt, " - "); found {
+		from, ok1 := parseVersion(lo)
+		to, ok2 := parseVersion(hi)
+		if !ok1 || !ok2 {
+			return false
+		}
+		b = b.intersect(bound{lo: from, hi: to.next()})
+		return b.nonEmpty()
+	}
+
+	for _, clause := range splitClauses(alt) {
+		op, raw := splitOperator(clause)
+		v, ok := parseVersion(raw)
+		if !ok {
+			return false
+		}
+		switch op {
+		case ">=":
+			b = b.intersect(bound{lo: v})
+		case ">":
+			b = b.intersect(bound{lo: v.next()})
+		case "<=":
+			b = b.intersect(bound{hi: v.next()})
+		case "<":
+			b = b.intersect(bound{hi: v})
+		case "!=":
+			// исключение отдельной версии выкидывает линию, только если накрывает её целиком
+			if len(v) <= len(line) && compareVersions(line.prefix(len(v)), v) == 0 {
+				return false
+			}
+		case "~=":
+			// PEP 440: ~=3.11 -> >=3.11,==3.*; ~=3.11.2 -> >=3.11.2,==3.11.*
+			if len(v) < 2 {
+				return false
+			}
+			b = b.intersect(bound{lo: v, hi: v.prefix(len(v) - 1).next()})
+		case "^":
+			// первый ненулевой компонент фиксирован: ^3.11 -> <4, ^0.2 -> <0.3
+			i := 0
+			for i < len(v)-1 && v[i] == 0 {
+				i++
+			}
+			b = b.intersect(bound{lo: v, hi: v.prefix(i + 1).next()})
+		case "~":
+			// ~1.2.3 -> <1.3, ~1.2 -> <1.3, ~1 -> <2
+			n := 2
+			if len(v) < 2 {
+				n = 1
+			}
+			b = b.intersect(bound{lo: v, hi: v.prefix(n).next()})
+		default:
+			// =, ==, голая версия или x-диапазон — вся линия v
+			b = b.intersect(bound{lo: v, hi: v.next()})
+		}
+		if !b.nonEmpty() {
+			return false
+		}
+	}
+	return b.nonEmpty()
+}
+
+func (b bound) intersect(o bound) bound {
+	if o.lo != nil && compareVersions(o.lo, b.lo) > 0 {
+		b.lo = o.lo
+	}
+	if o.hi != nil && (b.hi == nil || compareVersions(o.hi, b.hi) < 0) {
+		b.hi = o.hi
+	}
+	return b
+}
+
+func (b bound) nonEmpty() bool {
+	return b.hi == nil || compareVersions(b.lo, b.hi) < 0
+}
+
+// splitClauses делит ">= 3.9, <3.12" и ">=18 <21" на условия, приклеивая отдельно стоящий оператор к версии.
+func splitClauses(s string) []string {
+	var clauses []string
+	pending := ""
+	for _, f := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+		if strings.Trim(f, "<>=!~^") == "" {
+			pending += f
+			continue
+		}
+		clauses = append(clauses, pending+f)
+		pending = ""
+	}
+	return clauses
+}
+
+func splitOperator(clause string) (op, rest string) {
+	for _, candidate := range []string{">=", "<=", "==", "!=", "~=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(clause, candidate) {
+			return candidate, strings.TrimSpace(clause[len(candidate):])
+		}
+	}
+	return "", clause
+}
@@ -0,0 +1,300 @@
+// Package dockerlint — офлайн-линтер Dockerfile: теги latest, запуск от root, apt-get без очистки,
+// ADD по URL, пакеты без версий, отсутствие HEALTHCHECK, секреты в ENV/ARG, COPY . . до установки
+// зависимостей. У каждой находки есть подсказка исправления; отчёт выводится текстом, JSON
+// или в формате GitLab Code Quality, чтобы им можно было останавливать CI.
+package dockerlint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+)
+
+// Severity — уровни GitLab Code Quality, от слабого к сильному.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityMinor    Severity = "minor"
+	SeverityMajor    Severity = "major"
+	SeverityCritical Severity = "critical"
+	SeverityBlocker  Severity = "blocker"
+)
+
+var severityRank = map[Severity]int{
+	SeverityInfo: 1, SeverityMinor: 2, SeverityMajor: 3, SeverityCritical: 4, SeverityBlocker: 5,
+}
+
+// ParseSeverity принимает имя уровня; "none" — порог, которого не достигает ни одна находка.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if sev == "none" || severityRank[sev] > 0 {
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want info, minor, major, critical, blocker or none)", s)
+}
+
+// AtLeast сообщает, что уровень не ниже min.
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRank[min] > 0 && severityRank[s] >= severityRank[min]
+}
+
+// Finding — одна находка линтера.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"` // что поменять в Dockerfile
+	Source   string   `json:"source"`        // инструкция, к которой относится находка
+}
+
+// Failing — находки с уровнем не ниже min.
+func Failing(findings []Finding, min Severity) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if f.Severity.AtLeast(min) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// instruction — логическая строка Dockerfile: продолжения "\" склеены, тело heredoc добавлено к Args.
+type instruction struct {
+	Cmd  string // FROM, RUN, COPY, ... в верхнем регистре
+	Args string
+	Line int // строка начала инструкции
+	Text string
+}
+
+// stage — стейдж от FROM до следующего FROM.
+type stage struct {
+	From    instruction
+	Image   string // образ с подставленными ARG
+	Name    string // AS <name>
+	Parent  *stage // стейдж, от которого строится этот (FROM <stage>)
+	Instrs  []instruction
+	Exposed []string
+}
+
+var heredocRe = regexp.MustCompile(`<<-?\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+
+// App — что известно о приложении в образе из анализа репозитория; нулевое значение — ничего.
+// Используется только в подсказках исправлений.
+type App struct {
+	Port       string // порт приложения, если в финальном стейдже нет EXPOSE
+	HealthPath string // эндпоинт для HEALTHCHECK; "" — "/"
+}
+
+// LintFile читает и проверяет Dockerfile; file в находках — как передан.
+func LintFile(file string, cat *catalog.Catalog, app App) ([]Finding, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+	return Lint(file, content, cat, app), nil
+}
+
+// Lint проверяет содержимое Dockerfile. cat (может быть nil) подсказывает теги для образов рантаймов.
+func Lint(file string, content []byte, cat *catalog.Catalog, app App) []Finding {
+	instrs := parse(content)
+	stages := splitStages(instrs)
+	l := &linter{file: file, catalog: cat, app: app}
+	l.checkInstructions(instrs, stages)
+	l.checkFinalStage(stages)
+	sort.SliceStable(l.findings, func(i, j int) bool { return l.findings[i].Line < l.findings[j].Line })
+	return l.findings
+}
+
+// parse разбирает Dockerfile на инструкции. Комментарии внутри продолжений пропускаются, как у Docker.
+func parse(content []byte) []instruction {
+	var out []instruction
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	var cur *instruction
+	var heredocs []string
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Text()
+		if len(heredocs) > 0 {
+			cur.Args += "\n" + raw
+			cur.Text += "\n" + raw
+			if strings.TrimSpace(raw) == heredocs[0] {
+				heredocs = heredocs[1:]
+				if len(heredocs) == 0 {
+					out = append(out, *cur)
+					cur = nil
+				}
+			}
+			continue
+		}
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cont := strings.HasSuffix(line, "\\")
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\\"))
+		if cur == nil {
+			cmd, args, _ := strings.Cut(line, " ")
+			cur = &instruction{Cmd: strings.ToUpper(cmd), Args: strings.TrimSpace(args), Line: lineNo, Text: line}
+		} else {
+			cur.Args = strings.TrimSpace(cur.Args + " " + line)
+			cur.Text += " " + line
+		}
+		if cont {
+			continue
+		}
+		if cur.Cmd == "RUN" || cur.Cmd == "COPY" || cur.Cmd == "ADD" {
+			for _, m := range heredocRe.FindAllStringSubmatch(cur.Args, -1) {
+				heredocs = append(heredocs, m[1])
+			}
+			if len(heredocs) > 0 {
+				continue
+			}
+		}
+		out = append(out, *cur)
+		cur = nil
+	}
+	if cur != nil {
+		out = append(out, *cur)
+	}
+	return out
+}
+
+// splitStages делит инструкции по FROM; ARG до первого FROM подставляются в образы.
+func splitStages(instrs []instruction) []*stage {
+	globalArgs := map[string]string{}
+	byName := map[string]*stage{}
+	var stages []*stage
+	for _, in := range instrs {
+		if in.Cmd == "FROM" {
+			fields := flagFree(strings.Fields(in.Args))
+			if len(fields) == 0 {
+				continue
+			}
+			st := &stage{From: in, Image: expandArgs(fields[0], globalArgs)}
+			if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+				st.Name = strings.ToLower(fields[2])
+				byName[st.Name] = st
+			}
+			st.Parent = byName[strings.ToLower(st.Image)]
+			stages = append(stages, st)
+			continue
+		}
+		if len(stages) == 0 {
+			if in.Cmd == "ARG" {
+				for k, v := range parseKeyValues(in.Args) {
+					globalArgs[k] = v
+				}
+			}
+			continue
+		}
+		st := stages[len(stages)-1]
+		st.Instrs = append(st.Instrs, in)
+		if in.Cmd == "EXPOSE" {
+			for _, p := range strings.Fields(in.Args) {
+				st.Exposed = append(st.Exposed, strings.Split(p, "/")[0])
+			}
+		}
+	}
+	return stages
+}
+
+var argRefRe = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}?`)
+
+// expandArgs подставляет ${NAME}, $NAME и ${NAME:-default}; неизвестные переменные остаются как есть.
+func expandArgs(s string, args map[string]string) string {
+	return argRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+		m := argRefRe.FindStringSubmatch(ref)
+		if v, ok := args[m[1]]; ok && v != "" {
+			return v
+		}
+		if m[2] != "" {
+			return m[2]
+		}
+		return ref
+	})
+}
+
+// parseKeyValues: "A=1 B" (ARG) и "A=1 B=2" / "A 1" (ENV, LABEL); кавычки снимаются.
+func parseKeyValues(args string) map[string]string {
+	out := map[string]string{}
+	fields := splitQuoted(args)
+	if len(fields) >= 2 && !strings.Contains(fields[0], "=") && !strings.Contains(fields[1], "=") {
+		// ENV KEY value с пробелами
+		out[fields[0]] = unquote(strings.Join(fields[1:], " "))
+		return out
+	}
+	for _, f := range fields {
+		k, v, _ := strings.Cut(f, "=")
+		out[k] = unquote(v)
+	}
+	return out
+}
+
+// splitQuoted делит по пробелам вне кавычек.
+func splitQuoted(s string) []string {
+	var out []string
+	var b strings.Builder
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			b.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			b.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if b.Len() > 0 {
+				out = append(out, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		out = append(out, b.String())
+	}
+	return out
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// flagFree отбрасывает флаги инструкции (--platform, --from, --chown ...).
+func flagFree(fields []string) []string {
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		fields = fields[1:]
+	}
+	return fields
+}
+
+// instructionFlag — значение флага инструкции (--from=builder -> "builder").
+func instructionFlag(args, name string) (string, bool) {
+	for _, f := range strings.Fields(args) {
+		if !strings.HasPrefix(f, "--") {
+			break
+		}
+		if k, v, _ := strings.Cut(strings.TrimPrefix(f, "--"), "="); k == name {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,113 @@
+package dockerlint
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatText        Format = "text"
+	FormatJSON        Format = "json"
+	FormatCodeQuality Format = "codequality" // GitLab Code Quality (artifacts:reports:codequality)
+)
+
+// ParseFormat принимает имя формата и алиасы (gitlab, code-quality).
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "codequality", "code-quality", "gitlab":
+		return FormatCodeQuality, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want text, json or codequality)", s)
+}
+
+// Write выводит находки в формате format.
+func Write(w io.Writer, findings []Finding, format Format) error {
+	switch format {
+	case FormatText:
+		return writeText(w, findings)
+	case FormatJSON:
+		if findings == nil {
+			findings = []Finding{}
+		}
+		return writeJSON(w, findings)
+	case FormatCodeQuality:
+		return writeJSON(w, codeQuality(findings))
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// writeText: "Dockerfile:12: major root-user: ..." и строка с исправлением.
+func writeText(w io.Writer, findings []Finding) error {
+	var b strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&b, "%s:%d: %s %s: %s\n", f.File, f.Line, f.Severity, f.Rule, f.Message)
+		fmt.Fprintf(&b, "    %s\n", f.Source)
+		if f.Fix != "" {
+			fmt.Fprintf(&b, "    fix: %s\n", f.Fix)
+		}
+	}
+	if len(findings) == 0 {
+		b.WriteString("No Dockerfile issues found\n")
+	} else {
+		fmt.Fprintf(&b, "%d issue(s)\n", len(findings))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// codeQualityIssue — запись отчёта GitLab Code Quality (подмножество формата Code Climate).
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    Severity            `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// codeQuality: fingerprint не зависит от номера строки, чтобы находка не считалась новой после правок выше неё.
+func codeQuality(findings []Finding) []codeQualityIssue {
+	out := make([]codeQualityIssue, 0, len(findings))
+	seen := map[string]int{}
+	for _, f := range findings {
+		key := f.File + "|" + f.Rule + "|" + f.Source
+		seen[key]++
+		sum := md5.Sum([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+		issue := codeQualityIssue{
+			Description: f.Message,
+			CheckName:   "dockerlint/" + f.Rule,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    f.Severity,
+		}
+		if f.Fix != "" {
+			issue.Description += " (fix: " + f.Fix + ")"
+		}
+		issue.Location.Path = f.File
+		issue.Location.Lines.Begin = f.Line
+		out = append(out, issue)
+	}
+	return out
+}
@@ -0,0 +1,395 @@
+package dockerlint
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+)
+
+// Правила линтера
+const (
+	RuleLatestTag         = "latest-tag"
+	RuleRootUser          = "root-user"
+	RuleAptCleanup        = "apt-cleanup"
+	RuleAddURL            = "add-url"
+	RuleUnpinnedPackage   = "unpinned-package"
+	RuleMissingHealth     = "missing-healthcheck"
+	RuleSecretInEnv       = "secret-in-env"
+	RuleCopyBeforeInstall = "copy-before-install"
+)
+
+// catalogRuntimes — образы рантаймов из каталога, для которых можно подсказать тег.
+var catalogRuntimes = map[string]string{
+	"golang": catalog.RuntimeGo, "node": catalog.RuntimeNode, "python": catalog.RuntimePython,
+	"eclipse-temurin": catalog.RuntimeTemurin,
+}
+
+// secretNameRe — имена переменных, в которых обычно лежат секреты.
+var secretNameRe = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY|CREDENTIAL)`)
+
+// secretRefSuffixes — переменные, указывающие на секрет, а не содержащие его.
+var secretRefSuffixes = []string{"_FILE", "_PATH", "_DIR", "_URL", "_ENDPOINT", "_HEADER", "_NAME", "_ID"}
+
+// installCommands — установки зависимостей, кеш которых ломает COPY . . перед ними; манифесты — что копировать раньше.
+var installCommands = []struct {
+	re        *regexp.Regexp
+	manifests string
+}{
+	{regexp.MustCompile(`\bnpm\s+(ci|install|i)\b\s*($|&&|;|--)`), "package.json package-lock.json"},
+	{regexp.MustCompile(`\byarn(\s+install)?\s*($|&&|;|--)`), "package.json yarn.lock"},
+	{regexp.MustCompile(`\bpnpm\s+(install|i)\b`), "package.json pnpm-lock.yaml"},
+	{regexp.MustCompile(`\bpip3?\s+install\b.*\s(-r|--requirement)\s`), "requirements.txt"},
+	{regexp.MustCompile(`\bpoetry\s+install\b`), "pyproject.toml poetry.lock"},
+	{regexp.MustCompile(`\bpipenv\s+(install|sync)\b`), "Pipfile Pipfile.lock"},
+	{regexp.MustCompile(`\bgo\s+mod\s+download\b`), "go.mod go.sum"},
+	{regexp.MustCompile(`\bmvnw?\b.*\bdependency:(go-offline|resolve)\b`), "pom.xml"},
+	{regexp.MustCompile(`\bgradlew?\b.*\bdependencies\b`), "build.gradle settings.gradle"},
+	{regexp.MustCompile(`\bbundle\s+install\b`), "Gemfile Gemfile.lock"},
+	{regexp.MustCompile(`\bcomposer\s+install\b`), "composer.json composer.lock"},
+}
+
+var (
+	aptInstallRe  = regexp.MustCompile(`\bapt(-get)?\s+(-\S+\s+)*install\b`)
+	aptCleanupRe  = regexp.MustCompile(`rm\s+-(rf|fr|r)\s+/var/lib/apt/lists`)
+	aptCacheRe    = regexp.MustCompile(`--mount=type=cache[^ ]*target=/var/(lib|cache)/apt`)
+	apkAddRe      = regexp.MustCompile(`\bapk\s+(-\S+\s+)*add\b`)
+	commandSplitR = regexp.MustCompile(`&&|\|\||;|\||\n`)
+	runFlagsRe    = regexp.MustCompile(`^(--\S+\s+)+`)
+)
+
+type linter struct {
+	file     string
+	catalog  *catalog.Catalog
+	app      App
+	findings []Finding
+}
+
+func (l *linter) add(in instruction, rule string, sev Severity, msg, fix string) {
+	l.findings = append(l.findings, Finding{
+		Rule: rule, Severity: sev, File: l.file, Line: in.Line, Message: msg, Fix: fix, Source: firstLine(in.Text),
+	})
+}
+
+func (l *linter) checkInstructions(instrs []instruction, stages []*stage) {
+	for _, st := range stages {
+		l.checkFrom(st)
+		l.checkCopyBeforeInstall(st)
+	}
+	for _, in := range instrs {
+		switch in.Cmd {
+		case "RUN":
+			l.checkRun(in)
+		case "ADD":
+			l.checkAdd(in)
+		case "ENV", "ARG":
+			l.checkSecrets(in)
+		}
+	}
+}
+
+// checkFrom: образ без тега или с тегом latest.
+func (l *linter) checkFrom(st *stage) {
+	image := st.Image
+	if st.Parent != nil || strings.EqualFold(image, "scratch") || strings.Contains(image, "$") || strings.Contains(image, "@sha256:") {
+		return
+	}
+	repo, tag := splitImage(image)
+	if tag != "" && tag != "latest" {
+		return
+	}
+	msg := fmt.Sprintf("base image %s is not pinned: latest moves, builds are not reproducible", image)
+	fix := fmt.Sprintf("pin a version tag or digest: FROM %s:<version>", repo)
+	if rt, ok := catalogRuntimes[repoName(repo)]; ok && l.catalog != nil {
+		if r, ok := l.catalog.Resolve(rt, ""); ok {
+			fix = fmt.Sprintf("FROM %s:%s", repo, r.Version)
+		}
+	}
+	l.add(st.From, RuleLatestTag, SeverityMajor, msg, fix)
+}
+
+// checkRun: apt-get/apk без очистки кеша и пакеты без версий.
+func (l *linter) checkRun(in instruction) {
+	if aptInstallRe.MatchString(in.Args) && !aptCleanupRe.MatchString(in.Args) && !aptCacheRe.MatchString(in.Args) {
+		l.add(in, RuleAptCleanup, SeverityMinor,
+			"apt-get install leaves package lists in the layer",
+			"apt-get update && apt-get install -y --no-install-recommends ... && rm -rf /var/lib/apt/lists/* (in the same RUN)")
+	}
+	if apkAddRe.MatchString(in.Args) && !strings.Contains(in.Args, "--no-cache") && !strings.Contains(in.Args, "/var/cache/apk") {
+		l.add(in, RuleAptCleanup, SeverityMinor,
+			"apk add keeps the package index in the layer",
+			"apk add --no-cache ...")
+	}
+	var unpinned []string
+	for _, cmd := range commandSplitR.Split(runFlagsRe.ReplaceAllString(in.Args, ""), -1) {
+		unpinned = append(unpinned, unpinnedPackages(strings.Fields(cmd))...)
+	}
+	if len(unpinned) > 0 {
+		l.add(in, RuleUnpinnedPackage, SeverityMinor,
+			"packages installed without a version: "+strings.Join(unpinned, ", "),
+			"pin versions: apt-get install pkg=<version>, apk add pkg=<version>, pip install pkg==<version>, npm install -g pkg@<version>, go install pkg@<version>")
+	}
+}
+
+// unpinnedPackages — пакеты без версии в одной простой команде.
+func unpinnedPackages(fields []string) []string {
+	for len(fields) > 0 && (fields[0] == "sudo" || strings.Contains(fields[0], "=") && !strings.HasPrefix(fields[0], "-")) {
+		fields = fields[1:] // sudo, VAR=value перед командой
+	}
+	if len(fields) < 2 {
+		return nil
+	}
+	tool := fields[0]
+	var args []string
+	var pinned func(string) bool
+	switch {
+	case (tool == "apt-get" || tool == "apt") && contains(fields, "install"):
+		args = after(fields, "install")
+		pinned = func(p string) bool { return strings.Contains(p, "=") }
+	case tool == "apk" && contains(fields, "add"):
+		args = after(fields, "add")
+		pinned = func(p string) bool { return strings.ContainsAny(p, "=~<>") }
+	case (tool == "pip" || tool == "pip3" || strings.HasSuffix(tool, "/pip")) && contains(fields, "install"):
+		args = after(fields, "install")
+		for i := 0; i < len(args); i++ {
+			switch args[i] {
+			case "-r", "--requirement", "-c", "--constraint", "-e", "--editable", "-t", "--target", "--prefix", "--root", "-i", "--index-url", "--extra-index-url", "-f", "--find-links":
+				args = append(args[:i], args[min(i+2, len(args)):]...)
+				i--
+			}
+		}
+		pinned = func(p string) bool {
+			return strings.ContainsAny(p, "=<>~@") || strings.HasPrefix(p, ".") || strings.HasPrefix(p, "/") || strings.HasSuffix(p, ".whl") || strings.HasSuffix(p, ".tar.gz")
+		}
+	case tool == "npm" && (contains(fields, "-g") || contains(fields, "--global")) && (contains(fields, "install") || contains(fields, "i")):
+		args = after(fields, "install", "i")
+		pinned = func(p string) bool { return strings.Contains(strings.TrimPrefix(p, "@"), "@") }
+	case tool == "go" && fields[1] == "install":
+		args = fields[2:]
+		pinned = func(p string) bool { return strings.Contains(p, "@") && !strings.HasSuffix(p, "@latest") }
+	case tool == "gem" && fields[1] == "install":
+		if contains(fields, "-v") || contains(fields, "--version") {
+			return nil
+		}
+		args = fields[2:]
+		pinned = func(p string) bool { return strings.Contains(p, ":") }
+	default:
+		return nil
+	}
+	var out []string
+	for _, p := range args {
+		p = unquote(p)
+		if p == "" || strings.HasPrefix(p, "-") || strings.Contains(p, "$") || pinned(p) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// checkAdd: ADD по URL без --checksum.
+func (l *linter) checkAdd(in instruction) {
+	if _, ok := instructionFlag(in.Args, "checksum"); ok {
+		return
+	}
+	fields := flagFree(strings.Fields(in.Args))
+	if len(fields) < 2 {
+		return
+	}
+	for _, src := range fields[:len(fields)-1] {
+		if strings.Contains(src, "://") {
+			dest := fields[len(fields)-1]
+			if strings.HasSuffix(dest, "/") {
+				dest += path.Base(src)
+			}
+			l.add(in, RuleAddURL, SeverityMajor,
+				"ADD downloads "+src+" without verifying it and the result is not cached by content",
+				fmt.Sprintf("RUN curl -fsSLo %s %s && echo \"<sha256>  %s\" | sha256sum -c -   (or ADD --checksum=sha256:<sha256> %s %s)", dest, src, dest, src, fields[len(fields)-1]))
+			return
+		}
+	}
+}
+
+// checkSecrets: секреты в ENV (остаются в образе) и ARG (видны в docker history). Значения в отчёт не попадают.
+func (l *linter) checkSecrets(in instruction) {
+	values := parseKeyValues(in.Args)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		if !isSecretName(name) {
+			continue
+		}
+		fix := fmt.Sprintf("pass it at runtime (docker run -e / k8s Secret) or at build time via RUN --mount=type=secret,id=%s", strings.ToLower(name))
+		switch {
+		case in.Cmd == "ARG":
+			l.add(in, RuleSecretInEnv, SeverityMajor, "build argument "+name+" looks like a secret: build args are stored in the image history", fix)
+		case value != "" && !strings.HasPrefix(value, "$"):
+			l.add(in, RuleSecretInEnv, SeverityCritical, "ENV "+name+" bakes a secret value into the image", fix)
+		default:
+			l.add(in, RuleSecretInEnv, SeverityMajor, "ENV "+name+" copies a secret into the image environment", fix)
+		}
+	}
+}
+
+func isSecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, s := range secretRefSuffixes {
+		if strings.HasSuffix(upper, s) {
+			return false
+		}
+	}
+	return secretNameRe.MatchString(upper)
+}
+
+// checkCopyBeforeInstall: COPY . . до установки зависимостей — любое изменение исходников сбрасывает кеш установки.
+func (l *linter) checkCopyBeforeInstall(st *stage) {
+	var copyAll *instruction
+	for i, in := range st.Instrs {
+		if in.Cmd == "COPY" || in.Cmd == "ADD" {
+			if _, ok := instructionFlag(in.Args, "from"); ok {
+				continue
+			}
+			fields := flagFree(strings.Fields(in.Args))
+			if copyAll == nil && len(fields) >= 2 && containsAny(fields[:len(fields)-1], ".", "./") {
+				copyAll = &st.Instrs[i]
+			}
+			continue
+		}
+		if in.Cmd != "RUN" || copyAll == nil {
+			continue
+		}
+		for _, ic := range installCommands {
+			if ic.re.MatchString(in.Args) {
+				l.add(*copyAll, RuleCopyBeforeInstall, SeverityMinor,
+					fmt.Sprintf("COPY of the whole context before %q: any source change invalidates the dependency cache", strings.TrimSpace(firstLine(in.Args))),
+					fmt.Sprintf("COPY %s ./ ; RUN <install> ; then COPY . .", ic.manifests))
+				return
+			}
+		}
+	}
+}
+
+// checkFinalStage: пользователь и HEALTHCHECK итогового образа.
+func (l *linter) checkFinalStage(stages []*stage) {
+	if len(stages) == 0 {
+		return
+	}
+	final := stages[len(stages)-1]
+	base := final
+	for base.Parent != nil {
+		base = base.Parent
+	}
+	minimal := strings.EqualFold(base.Image, "scratch") || strings.Contains(base.Image, "distroless")
+
+	user, userInstr := "", final.From
+	for st := final; st != nil && user == ""; st = st.Parent {
+		for _, in := range st.Instrs {
+			if in.Cmd == "USER" {
+				user, userInstr = strings.TrimSpace(in.Args), in
+			}
+		}
+	}
+	switch {
+	case user == "" && strings.Contains(base.Image, ":nonroot"):
+	case user == "":
+		l.add(final.From, RuleRootUser, SeverityMajor, "final image runs as root: no USER instruction", rootFix(base.Image))
+	case isRootUser(user):
+		l.add(userInstr, RuleRootUser, SeverityMajor, "final image runs as root: USER "+user, rootFix(base.Image))
+	}
+
+	for st := final; st != nil; st = st.Parent {
+		for _, in := range st.Instrs {
+			if in.Cmd == "HEALTHCHECK" {
+				return
+			}
+		}
+	}
+	// EXPOSE финального стейджа, затем порт из анализа; 8080 — только если не известно ничего
+	port := cmp.Or(l.app.Port, "8080")
+	if len(final.Exposed) > 0 {
+		port = final.Exposed[0]
+	}
+	route := cmp.Or(l.app.HealthPath, "/")
+	if minimal {
+		l.add(final.From, RuleMissingHealth, SeverityInfo,
+			"no HEALTHCHECK: the image has no shell or HTTP client for one",
+			`rely on orchestrator probes, or add a check mode to the binary: HEALTHCHECK CMD ["/app/app", "healthcheck"]`)
+		return
+	}
+	l.add(final.From, RuleMissingHealth, SeverityMinor,
+		"no HEALTHCHECK: docker and compose cannot tell a hung container from a healthy one",
+		fmt.Sprintf("HEALTHCHECK --interval=30s --timeout=3s --start-period=10s CMD wget -qO- http://127.0.0.1:%s%s >/dev/null || exit 1", port, route))
+}
+
+func isRootUser(user string) bool {
+	name, _, _ := strings.Cut(user, ":")
+	return name == "root" || name == "0"
+}
+
+// rootFix — как завести непривилегированного пользователя на данной базе.
+func rootFix(image string) string {
+	switch {
+	case strings.Contains(image, "distroless"):
+		return "use the :nonroot tag or add USER 65532:65532"
+	case strings.EqualFold(image, "scratch"):
+		return "add USER 65532:65532 (files copied with --chown=65532:65532)"
+	case strings.Contains(image, "alpine"):
+		return "RUN addgroup -S -g 10001 app && adduser -S -u 10001 -G app app ; USER 10001:10001"
+	}
+	return "RUN groupadd --system --gid 10001 app && useradd --system --uid 10001 --gid app app ; USER 10001:10001"
+}
+
+// splitImage: "registry:5000/org/app:1.2" -> ("registry:5000/org/app", "1.2").
+func splitImage(image string) (repo, tag string) {
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		return image[:colon], image[colon+1:]
+	}
+	return image, ""
+}
+
+// repoName — имя образа без реестра и library/.
+func repoName(repo string) string {
+	return repo[strings.LastIndex(repo, "/")+1:]
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func contains(fields []string, s string) bool {
+	for _, f := range fields {
+		if f == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(fields []string, values ...string) bool {
+	for _, v := range values {
+		if contains(fields, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// after — аргументы после первого из слов words.
+func after(fields []string, words ...string) []string {
+	for i, f := range fields {
+		if contains(words, f) {
+			return append([]string(nil), fields[i+1:]...)
+		}
+	}
+	return nil
+}
@@ -1,14 +1,36 @@
 package dto
 
+import "strconv"
+
 // AnalyzeDTO — единый контракт между анализатором и генератором.
+// Один DTO описывает один модуль (go.mod, pom.xml, package.json, pyproject.toml, ...).
 type AnalyzeDTO struct {
 	// Общая часть
-	Language        string   `json:"language"`         // см. constants.go
-	Framework       string   `json:"framework"`        // общая наводка
-	BuildTool       string   `json:"build_tool"`       // для java/go и т.п.
-	LanguageVersion string   `json:"language_version"` // best-effort
-	AppPort         int      `json:"app_port"`
-	DetectedFiles   []string `json:"detected_files"`
+	Name             string   `json:"name"`
+	ModulePath       string   `json:"module_path"`       // манифест модуля
+	Language         string   `json:"language"`          // см. constants.go
+	Framework        string   `json:"framework"`         // общая наводка
+	FrameworkVersion string   `json:"framework_version"` // best-effort
+	BuildTool        string   `json:"build_tool"`        // для java/go и т.п.
+	LanguageVersion  string   `json:"language_version"`  // best-effort
+	AppPort          int      `json:"app_port"`
+	HealthPath       string   `json:"health_path,omitempty"` // эндпоинт проверки здоровья, "" — не найден
+	DetectedFiles    []string `json:"detected_files"`
+	Dependencies     []string `json:"dependencies"`
+	Services         []string `json:"services"` // см. Service* в constants.go
+
+	// Сборка и запуск
+	BuildCommand string        `json:"build_command"`
+	TestCommand  string        `json:"test_command"`
+	StartCommand string        `json:"start_command"`
+	ArtifactPath string        `json:"artifact_path"`
+	BuilderImage string        `json:"builder_image"`
+	RuntimeImage string        `json:"runtime_image"`
+	BuildTargets []BuildTarget `json:"build_targets,omitempty"`
+	// Многомодульная сборка: каталог сборки, подмодуль для -pl / подпроект Gradle / пакет workspace, задача Gradle
+	BuildRoot     string `json:"build_root,omitempty"`
+	ReactorModule string `json:"reactor_module,omitempty"`
+	BuildTask     string `json:"build_task,omitempty"`
 
 	// Детали по языкам
 	Python *PythonMeta `json:"python,omitempty"`
@@ -27,3 +49,22 @@ func (a AnalyzeDTO) CanonicalLanguage() string {
 		return a.Language
 	}
 }
+
+// Port — AppPort строкой; "" — порт неизвестен.
+func (a AnalyzeDTO) Port() string {
+	if a.AppPort <= 0 {
+		return ""
+	}
+	return strconv.Itoa(a.AppPort)
+}
+
+// StaticSite — фронтенд, который раздаётся веб-сервером, а не запускается через node.
+func (a AnalyzeDTO) StaticSite() bool {
+	return a.Node != nil && a.Node.StaticSite
+}
+
+// BuildTarget — один собираемый бинарник: имя и путь пакета относительно модуля.
+type BuildTarget struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // "./cmd/api", "."
+}
@@ -7,6 +7,8 @@ const (
 	LangTypeScript = "typescript" // алиас к "node"
 	LangPython     = "python"
 	LangJava       = "java"
+	LangPHP        = "php"
+	LangRuby       = "ruby"
 )
 
 // Менеджеры пакетов / инструменты сборки
@@ -17,6 +19,12 @@ const (
 	PyPip    = "pip"
 	PyPoetry = "poetry"
 	PyPipenv = "pipenv"
+
+	BuildMaven     = "maven"
+	BuildGradle    = "gradle"
+	BuildGoModules = "go-modules"
+	BuildComposer  = "composer"
+	BuildBundler   = "bundler"
 )
 
 // Варианты базовых образов
@@ -26,3 +34,13 @@ const (
 	BaseDistroless = "distroless"
 	BaseScratch    = "scratch"
 )
+
+// Внешние сервисы, которые поднимаются рядом с приложением
+const (
+	ServicePostgres = "postgres"
+	ServiceMySQL    = "mysql"
+	ServiceRedis    = "redis"
+	ServiceKafka    = "kafka"
+	ServiceRabbitMQ = "rabbitmq"
+	ServiceMongo    = "mongo"
+)
@@ -1,10 +1,51 @@
 package dto
 
+import "strings"
+
 // DockerMeta — предпочтения/детекты контейнеризации.
 type DockerMeta struct {
 	DockerfileDetected bool   `json:"dockerfile_detected"`
+	DockerfilePath     string `json:"dockerfile_path"` // существующий Dockerfile модуля относительно репозитория
+	DockerContext      string `json:"docker_context"`  // контекст его сборки относительно репозитория ("." — корень)
 	ComposeDetected    bool   `json:"compose_detected"`
 	PreferredBase      string `json:"preferred_base"` // "alpine"|"slim"|"distroless"|"scratch"
-	ExposedPort        int    `json:"exposed_port"`
+	ExposedPort        int    `json:"exposed_port"`   // EXPOSE из существующего Dockerfile
 	ImageName          string `json:"image_name"`
 }
+
+// GeneratedDockerfile — Dockerfile, который генераторы пишут, когда в репозитории своего нет.
+const GeneratedDockerfile = "gentmp/Dockerfile"
+
+// Dockerfile — Dockerfile сборки образа относительно корня репозитория: свой или сгенерированный.
+func (d DockerMeta) Dockerfile() string {
+	if d.DockerfilePath != "" {
+		return d.DockerfilePath
+	}
+	return GeneratedDockerfile
+}
+
+// Context — контекст сборки относительно корня репозитория.
+func (d DockerMeta) Context() string {
+	if d.DockerfilePath != "" && d.DockerContext != "" {
+		return d.DockerContext
+	}
+	return "."
+}
+
+// BuildArgs — "-f <Dockerfile> <контекст>" для docker build из корня репозитория.
+func (d DockerMeta) BuildArgs() string {
+	return "-f " + d.Dockerfile() + " " + d.Context()
+}
+
+// ContextDockerfile — Dockerfile относительно контекста (build.dockerfile в compose).
+func (d DockerMeta) ContextDockerfile() string {
+	ctx := d.Context()
+	if ctx == "." {
+		return d.Dockerfile()
+	}
+	if rel, ok := strings.CutPrefix(d.Dockerfile(), ctx+"/"); ok {
+		return rel
+	}
+	// Dockerfile вне контекста: путь от контекста через ../
+	return strings.Repeat("../", strings.Count(ctx, "/")+1) + d.Dockerfile()
+}
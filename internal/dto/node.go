@@ -12,4 +12,6 @@ type NodeMeta struct {
 	LintScript   bool   `json:"lint_script"`
 	BuildDir     string `json:"build_dir"`     // "dist"|"build"|"" — для Docker/артефактов
 	StartCommand string `json:"start_command"` // "node dist/index.js" и т.п.
+	StaticSite   bool   `json:"static_site"`   // собирается в BuildDir и раздаётся nginx
+	Lockfile     string `json:"lockfile"`      // package-lock.json|yarn.lock|pnpm-lock.yaml|""
 }
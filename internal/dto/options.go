@@ -14,4 +14,13 @@ type GenerationOptions struct {
 	NexusURL      string `json:"nexus_url"`
 	NexusUser     string `json:"nexus_user"`
 	NexusPassword string `json:"nexus_password"`
+
+	Hardening Hardening `json:"hardening"`
+}
+
+// Hardening — усиленный профиль (--hardened): образы под непривилегированным UID с HEALTHCHECK и STOPSIGNAL,
+// в compose и k8s — read-only корневая ФС, запрет повышения привилегий и сброс capabilities.
+type Hardening struct {
+	Enabled bool   `json:"enabled"`
+	Base    string `json:"base,omitempty"` // BaseDistroless|BaseScratch — рантайм без shell; "" — обычный образ языка
 }
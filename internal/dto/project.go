@@ -0,0 +1,96 @@
+package dto
+
+// ProjectDTO — весь репозиторий для генераторов: модули и общие для них граф сборки и окружение.
+type ProjectDTO struct {
+	RepoName       string        `json:"repo_name"`
+	Strategy       string        `json:"strategy"` // "monorepo"|"standalone"
+	Modules        []*AnalyzeDTO `json:"modules"`
+	BuildGraphs    []BuildGraph  `json:"build_graphs,omitempty"`
+	EnvVars        []EnvVar      `json:"env_vars"`
+	Infrastructure []string      `json:"infrastructure"`
+	KustomizeFiles []string      `json:"kustomize_files,omitempty"` // существующие kustomization.yaml относительно репозитория
+
+	// Options — параметры запуска генерации (флаги CLI), не результат анализа.
+	Options GenerationOptions `json:"options"`
+}
+
+// BuildGraph — граф модулей многомодульной сборки (Maven reactor, Gradle multi-project, Node workspaces).
+type BuildGraph struct {
+	BuildTool    string           `json:"build_tool"`
+	Root         string           `json:"root"`                   // относительно репозитория
+	Orchestrator string           `json:"orchestrator,omitempty"` // turbo, nx
+	Nodes        []BuildGraphNode `json:"nodes"`
+}
+
+// BuildGraphNode — один модуль сборки и его зависимости внутри графа.
+type BuildGraphNode struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"` // относительно BuildGraph.Root, "." для корня
+	Packaging string   `json:"packaging"`
+	Framework string   `json:"framework,omitempty"`
+	Runnable  bool     `json:"runnable"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	Scripts   []string `json:"scripts,omitempty"`
+}
+
+// EnvVar — переменная окружения, которую читает приложение.
+type EnvVar struct {
+	Name       string   `json:"name"`
+	Default    string   `json:"default,omitempty"`
+	HasDefault bool     `json:"has_default"`
+	Required   bool     `json:"required"`
+	Secret     bool     `json:"secret"`
+	Modules    []string `json:"modules,omitempty"` // пусто — переменная общая для всех модулей
+	Sources    []string `json:"sources"`           // "config/config.go:12", ".env.example:3"
+}
+
+// ModuleFor возвращает первый модуль языка; JS и TS считаются одним стеком.
+func (p *ProjectDTO) ModuleFor(lang string) *AnalyzeDTO {
+	if p == nil {
+		return nil
+	}
+	want := AnalyzeDTO{Language: lang}.CanonicalLanguage()
+	for _, m := range p.Modules {
+		if m.CanonicalLanguage() == want {
+			return m
+		}
+	}
+	return nil
+}
+
+// ModuleEnvVars возвращает переменные, которые читает модуль, плюс общие из .env.example.
+func (p *ProjectDTO) ModuleEnvVars(m *AnalyzeDTO) []EnvVar {
+	if p == nil {
+		return nil
+	}
+	var out []EnvVar
+	for _, v := range p.EnvVars {
+		if len(v.Modules) == 0 || (m != nil && containsString(v.Modules, m.Name)) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// BuildGraphFor возвращает граф сборки, в который входит модуль, или nil.
+func (p *ProjectDTO) BuildGraphFor(m *AnalyzeDTO) *BuildGraph {
+	if p == nil || m == nil || m.ReactorModule == "" {
+		return nil
+	}
+	for i := range p.BuildGraphs {
+		g := &p.BuildGraphs[i]
+		if g.Root == m.BuildRoot && g.BuildTool == m.BuildTool {
+			return g
+		}
+	}
+	return nil
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,434 @@
+package compose_generators
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// devProfile — отличия сервиса приложения в docker-compose.dev.yml; в docker-compose.yml пустой.
+type devProfile struct {
+	Target    string   // стейдж мультистейдж Dockerfile
+	Command   string   // YAML flow-список аргументов
+	WorkDir   string   // "" — WORKDIR образа
+	Volumes   []string // исходники и кеши зависимостей
+	DebugPort string
+	Env       map[string]string
+	Named     []string // именованные тома из Volumes для секции volumes
+}
+
+// Порты отладчиков: delve, node inspector, debugpy, JDWP.
+const (
+	debugPortGo     = "2345"
+	debugPortNode   = "9229"
+	debugPortPython = "5678"
+	debugPortJava   = "5005"
+)
+
+const jdwpAgent = "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=*:" + debugPortJava
+
+// devStageRe — стейдж dev в Dockerfile (FROM <image> AS dev).
+var devStageRe = regexp.MustCompile(`(?im)^FROM\s+\S+\s+AS\s+dev\s*$`)
+
+// GenerateComposeDev собирает gentmp/docker-compose.dev.yml для локальной разработки: приложение
+// собирается из стейджа dev Dockerfile модуля (сгенерированного или своего), исходники монтируются в контейнер,
+// команда с перезагрузкой выбирается по фреймворку (air, nodemon/tsx, dev-сервер фреймворка,
+// uvicorn --reload/manage.py runserver/flask --debug, spring-boot:run с devtools),
+// порт отладчика публикуется. Бэкинг-сервисы те же, что в docker-compose.yml.
+// Без стейджа dev (PHP, Ruby, Dockerfile репозитория без него) файл не создаётся и возвращается "".
+func GenerateComposeDev(repoName, repoRoot, lang string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir gentmp: %w", err)
+	}
+	outPath := filepath.Join(tmpDir, "docker-compose.dev.yml")
+
+	module := selectModule(analysis, lang)
+	if module == nil {
+		return "", fmt.Errorf("no %s module for compose", lang)
+	}
+	src := module.Docker.Dockerfile()
+	if module.Docker.DockerfilePath != "" {
+		src = filepath.Join(repoRoot, src)
+	}
+	dockerfile, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", module.Docker.Dockerfile(), err)
+	}
+	if !devStageRe.Match(dockerfile) {
+		// шаблоны PHP и Ruby стейджа dev не содержат — это не ошибка, dev-окружения просто нет
+		fmt.Printf("docker-compose.dev.yml: %s has no dev stage (FROM ... AS dev), skipping\n", module.Docker.Dockerfile())
+		return "", nil
+	}
+
+	var dev devProfile
+	switch composeLangDir(lang) {
+	case dto.LangGo:
+		dev = goDevProfile(repoRoot, module)
+	case dto.LangNode:
+		dev, err = nodeDevProfile(module)
+	case dto.LangPython:
+		dev, err = pythonDevProfile(repoRoot, module)
+	case dto.LangJava:
+		dev, err = javaDevProfile(module)
+	default:
+		return "", fmt.Errorf("no dev profile for %s", lang)
+	}
+	if err != nil {
+		return "", err
+	}
+	dev.Target = "dev"
+
+	env, dependsOn, volumes := appWiring(lang, module, analysis)
+	for k, v := range dev.Env {
+		env[k] = v
+	}
+	volumes = append(volumes, dev.Named...)
+	sort.Strings(volumes)
+
+	serviceName := sanitizeServiceName(repoName)
+	port := module.Port()
+	if port == "" && module.StaticSite() {
+		port = "8080"
+	}
+	buildCtx, dockerfilePath := buildContext(module)
+	appData := map[string]any{
+		"ServiceName": serviceName,
+		"Context":     buildCtx,
+		"Dockerfile":  dockerfilePath,
+		"Image":       serviceName + ":dev",
+		"Port":        port,
+		"Env":         env,
+		"DependsOn":   dependsOn,
+		"Dev":         dev,
+		"Hardening":   composeHardening{},
+	}
+	header := "# docker-compose.dev.yml — сгенерировано gogen-self-deploy, для локальной разработки\n" +
+		"# docker compose -f gentmp/docker-compose.dev.yml up --build; исходники монтируются из репозитория\n"
+	if dev.DebugPort != "" {
+		header += "# Отладчик: localhost:" + dev.DebugPort + "\n"
+	}
+	return writeCompose(outPath, header, lang, appData, dependsOn, volumes)
+}
+
+// goDevProfile: air пересобирает пакет без оптимизаций и перезапускает его под dlv.
+func goDevProfile(repoRoot string, m *dto.AnalyzeDTO) devProfile {
+	target := "."
+	if len(m.BuildTargets) > 0 {
+		target = m.BuildTargets[0].Path
+		base := filepath.Base(m.Name)
+		for _, t := range m.BuildTargets {
+			if t.Name == base {
+				target = t.Path
+				break
+			}
+		}
+	}
+	return devProfile{
+		Command: flowList(
+			"air",
+			"--build.cmd", "go build -gcflags='all=-N -l' -o ./tmp/app "+target,
+			"--build.full_bin", "dlv exec ./tmp/app --headless --listen=:"+debugPortGo+" --api-version=2 --accept-multiclient --continue",
+		),
+		WorkDir:   moduleWorkDir(repoRoot, m),
+		Volumes:   []string{"..:/app", "go_mod_cache:/go/pkg/mod", "go_build_cache:/root/.cache/go-build"},
+		Named:     []string{"go_mod_cache", "go_build_cache"},
+		DebugPort: debugPortGo,
+		Env:       map[string]string{"APP_ENV": "development"},
+	}
+}
+
+// nodeDevProfile: dev-сервер фреймворка, для статики — без инспектора; для остального
+// tsx watch (TypeScript) или nodemon. node_modules остаются из образа (анонимные тома).
+func nodeDevProfile(m *dto.AnalyzeDTO) (devProfile, error) {
+	port := m.Port()
+	if port == "" {
+		port = "3000"
+		if m.StaticSite() {
+			port = "8080"
+		}
+	}
+	dev := devProfile{
+		Volumes: []string{"..:/app", "/app/node_modules"},
+		Env:     map[string]string{"NODE_ENV": "development", "CHOKIDAR_USEPOLLING": "true", "WATCHPACK_POLLING": "true"},
+	}
+	if m.ReactorModule != "" {
+		dev.WorkDir = "/app/" + m.ReactorModule
+		dev.Volumes = append(dev.Volumes, dev.WorkDir+"/node_modules")
+	}
+	pkg := readPackageJSON(m.ModulePath)
+	inspect := "--inspect=0.0.0.0:" + debugPortNode
+
+	switch {
+	case m.Framework == "NestJS":
+		dev.Command = flowList("npx", "nest", "start", "--watch", "--debug", "0.0.0.0:"+debugPortNode)
+	case m.Framework == "Next.js":
+		dev.Command = flowList("npx", "next", "dev", "-H", "0.0.0.0", "-p", port)
+	case m.Framework == "Nuxt":
+		dev.Command = flowList("npx", "nuxi", "dev", "--host", "0.0.0.0", "--port", port)
+	case m.StaticSite():
+		switch {
+		case m.Framework == "Angular":
+			dev.Command = flowList("npx", "ng", "serve", "--host", "0.0.0.0", "--port", port, "--poll", "1000")
+		case pkg.has("react-scripts"):
+			dev.Command = flowList("npx", "react-scripts", "start")
+			dev.Env["HOST"], dev.Env["PORT"] = "0.0.0.0", port
+		case pkg.has("@vue/cli-service"):
+			dev.Command = flowList("npx", "vue-cli-service", "serve", "--host", "0.0.0.0", "--port", port)
+		default:
+			dev.Command = flowList("npx", "vite", "dev", "--host", "0.0.0.0", "--port", port)
+		}
+		// код выполняется в браузере — отладчик node не нужен
+		return dev, nil
+	default:
+		moduleDir := filepath.Dir(m.ModulePath)
+		entry, src := nodeEntry(moduleDir, m.StartCommand, pkg)
+		switch {
+		case src != "":
+			dev.Command = flowList("tsx", "watch", inspect, src)
+		case entry != "":
+			dev.Command = flowList("nodemon", inspect, entry)
+		case pkg.Scripts["dev"] != "":
+			// точка входа не найдена — полагаемся на скрипт автора, без отладчика
+			dev.Command = flowList(nodeRunner(m.BuildTool), "run", "dev")
+			return dev, nil
+		default:
+			return dev, fmt.Errorf("no node entry point for docker-compose.dev.yml (start command %q)", m.StartCommand)
+		}
+	}
+	if m.Framework == "Next.js" || m.Framework == "Nuxt" {
+		// dev-серверы сами запускают node — инспектор через NODE_OPTIONS
+		dev.Env["NODE_OPTIONS"] = inspect
+	}
+	dev.DebugPort = debugPortNode
+	return dev, nil
+}
+
+// nodeEntry ищет точку входа по команде запуска, scripts.start и main: собранный JS
+// сопоставляется с исходником TypeScript, иначе берётся существующий JS-файл.
+func nodeEntry(moduleDir, startCommand string, pkg packageJSON) (entry, src string) {
+	candidates := []string{startCommand, pkg.Scripts["start"], pkg.Main, "index.js", "src/index.js"}
+	for _, c := range candidates {
+		c = strings.TrimSpace(c)
+		if c == "" || (strings.Contains(c, " ") && !strings.HasPrefix(c, "node ")) {
+			continue
+		}
+		c = strings.TrimPrefix(c, "node ")
+		if strings.Contains(c, " ") {
+			continue
+		}
+		if src := tsSource(moduleDir, c); src != "" {
+			return "", src
+		}
+		if fileExists(filepath.Join(moduleDir, c)) {
+			return c, ""
+		}
+	}
+	return "", ""
+}
+
+// tsSource: "dist/index.js" -> "src/index.ts", если такой файл есть в модуле.
+func tsSource(moduleDir, entry string) string {
+	for _, out := range []string{"dist/", "build/", ""} {
+		if !strings.HasPrefix(entry, out) {
+			continue
+		}
+		base := strings.TrimSuffix(strings.TrimPrefix(entry, out), ".js")
+		for _, src := range []string{"src/" + base + ".ts", base + ".ts"} {
+			if fileExists(filepath.Join(moduleDir, src)) {
+				return src
+			}
+		}
+	}
+	return ""
+}
+
+func nodeRunner(buildTool string) string {
+	switch buildTool {
+	case dto.PmPnpm, dto.PmYarn:
+		return buildTool
+	}
+	return "npm"
+}
+
+// pythonDevProfile: Django — manage.py runserver, ASGI — uvicorn --reload, Flask — flask run --debug,
+// остальное — команда запуска под watchfiles. Все под debugpy.
+func pythonDevProfile(repoRoot string, m *dto.AnalyzeDTO) (devProfile, error) {
+	port := m.Port()
+	if port == "" {
+		port = "8000"
+	}
+	dev := devProfile{
+		WorkDir:   moduleWorkDir(repoRoot, m),
+		Volumes:   []string{"..:/app"},
+		DebugPort: debugPortPython,
+	}
+	debugpy := []string{"python", "-m", "debugpy", "--listen", "0.0.0.0:" + debugPortPython}
+	var meta dto.PythonMeta
+	if m.Python != nil {
+		meta = *m.Python
+	}
+	appDir := startCommandFlag(m.StartCommand, "--app-dir", "--chdir")
+
+	var args []string
+	switch {
+	case meta.Framework == "django" && fileExists(filepath.Join(filepath.Dir(m.ModulePath), "manage.py")):
+		args = append(debugpy, "manage.py", "runserver", "0.0.0.0:"+port)
+	case meta.AsgiModule != "":
+		args = append(debugpy, "-m", "uvicorn", meta.AsgiModule, "--reload", "--host", "0.0.0.0", "--port", port)
+		if appDir != "" {
+			args = append(args, "--app-dir", appDir)
+		}
+	case meta.Framework == "flask" && meta.WsgiModule != "":
+		args = append(debugpy, "-m", "flask", "--app", meta.WsgiModule, "run", "--debug", "--no-debugger", "--host", "0.0.0.0", "--port", port)
+		if appDir != "" {
+			// flask --app ищет модуль относительно рабочего каталога
+			dev.WorkDir = cmp.Or(dev.WorkDir, "/app") + "/" + strings.Trim(appDir, "/")
+		}
+	case strings.HasPrefix(m.StartCommand, "python "):
+		args = []string{"watchfiles", "--filter", "python", strings.Join(debugpy, " ") + strings.TrimPrefix(m.StartCommand, "python")}
+	case m.StartCommand != "":
+		args = []string{"watchfiles", "--filter", "python", m.StartCommand}
+		dev.DebugPort = ""
+	default:
+		return dev, fmt.Errorf("no python start command for docker-compose.dev.yml")
+	}
+	if m.BuildTool == dto.PyPoetry && (m.Python == nil || !m.Python.HasRequirements) {
+		args = append([]string{"poetry", "run"}, args...)
+	}
+	dev.Command = flowList(args...)
+	return dev, nil
+}
+
+// javaDevProfile: spring-boot:run/bootRun с JDWP и devtools, quarkus:dev/quarkusDev со своим live reload.
+// Исходники — корень сборки (reactor / multi-project), кеш зависимостей — именованный том.
+func javaDevProfile(m *dto.AnalyzeDTO) (devProfile, error) {
+	dev := devProfile{DebugPort: debugPortJava}
+	source := ".."
+	if root := strings.Trim(m.BuildRoot, "/"); root != "" && root != "." {
+		source = "../" + root
+	}
+	quarkus := []string{"-Ddebug=" + debugPortJava, "-DdebugHost=0.0.0.0", "-Dquarkus.http.host=0.0.0.0"}
+
+	if m.BuildTool == dto.BuildGradle {
+		dev.Volumes = []string{source + ":/app", "gradle_home:/home/gradle/.gradle"}
+		dev.Named = []string{"gradle_home"}
+		prefix := ""
+		if i := strings.LastIndex(m.BuildTask, ":"); i >= 0 {
+			prefix = m.BuildTask[:i+1]
+		}
+		switch m.Framework {
+		case "Spring Boot":
+			dev.Command = flowList("gradle", "--no-daemon", "--init-script", "/opt/gradle-dev/jdwp.gradle", prefix+"bootRun")
+		case "Quarkus":
+			dev.Command = flowList(append([]string{"gradle", "--no-daemon", prefix + "quarkusDev"}, quarkus...)...)
+		default:
+			dev.Command = flowList("gradle", "--no-daemon", "--init-script", "/opt/gradle-dev/jdwp.gradle", prefix+"run")
+		}
+		springDevtoolsHint(m)
+		return dev, nil
+	}
+
+	dev.Volumes = []string{source + ":/app", "maven_repo:/root/.m2"}
+	dev.Named = []string{"maven_repo"}
+	var goal []string
+	switch m.Framework {
+	case "Spring Boot":
+		goal = []string{"spring-boot:run", "-Dspring-boot.run.jvmArguments=" + jdwpAgent}
+	case "Quarkus":
+		goal = append([]string{"quarkus:dev"}, quarkus...)
+	default:
+		return dev, fmt.Errorf("no maven dev goal for framework %q", m.Framework)
+	}
+	if m.ReactorModule == "" {
+		dev.Command = flowList(append([]string{"mvn", "-B"}, goal...)...)
+	} else {
+		// модули, от которых зависит приложение, сначала ставятся в ~/.m2
+		quoted := make([]string, len(goal))
+		for i, g := range goal {
+			quoted[i] = "'" + g + "'"
+		}
+		dev.Command = flowList("sh", "-c",
+			"mvn -B -pl "+m.ReactorModule+" -am install -DskipTests && exec mvn -B -pl "+m.ReactorModule+" "+strings.Join(quoted, " "))
+	}
+	springDevtoolsHint(m)
+	return dev, nil
+}
+
+// springDevtoolsHint предупреждает, что без spring-boot-devtools приложение не перезапускается при изменении классов.
+func springDevtoolsHint(m *dto.AnalyzeDTO) {
+	if m.Framework != "Spring Boot" {
+		return
+	}
+	for _, d := range m.Dependencies {
+		if strings.HasSuffix(d, "spring-boot-devtools") || strings.Contains(d, ":spring-boot-devtools:") {
+			return
+		}
+	}
+	fmt.Println("docker-compose.dev.yml: add org.springframework.boot:spring-boot-devtools to restart on class changes (compiled by the IDE into the mounted target/ or build/)")
+}
+
+// moduleWorkDir — каталог модуля в контейнере, если модуль не в корне репозитория.
+func moduleWorkDir(repoRoot string, m *dto.AnalyzeDTO) string {
+	rel, err := filepath.Rel(repoRoot, filepath.Dir(m.ModulePath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return "/app/" + filepath.ToSlash(rel)
+}
+
+// startCommandFlag возвращает значение первого из флагов в команде запуска ("--app-dir src" -> "src").
+func startCommandFlag(cmd string, flags ...string) string {
+	fields := strings.Fields(cmd)
+	for i := 0; i+1 < len(fields); i++ {
+		for _, f := range flags {
+			if fields[i] == f {
+				return fields[i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// flowList — команда YAML flow-списком; $ экранируется от интерполяции compose.
+func flowList(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = strconv.Quote(strings.ReplaceAll(a, "$", "$$"))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+type packageJSON struct {
+	Main            string            `json:"main"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+func readPackageJSON(path string) packageJSON {
+	var pkg packageJSON
+	if b, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(b, &pkg)
+	}
+	return pkg
+}
+
+func (p packageJSON) has(dep string) bool {
+	_, inDeps := p.Dependencies[dep]
+	_, inDev := p.DevDependencies[dep]
+	return inDeps || inDev
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,49 @@
+package compose_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// GenerateEnvExample рендерит gentmp/.env.example из инвентаря переменных окружения
+// (шаблон templates/snippets/env/dotenv_example.tmpl). Секреты выводятся без значений.
+func GenerateEnvExample(lang string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir gentmp: %w", err)
+	}
+	outPath := filepath.Join(tmpDir, ".env.example")
+
+	var envVars []dto.EnvVar
+	if analysis != nil {
+		envVars = analysis.ModuleEnvVars(selectModule(analysis, lang))
+	}
+
+	raw, err := os.ReadFile(filepath.Join("templates", "snippets", "env", "dotenv_example.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("read env example template: %w", err)
+	}
+	tpl, err := template.New("env-example").Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse env example template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, map[string]any{"EnvVars": envVars}); err != nil {
+		return "", fmt.Errorf("render env example: %w", err)
+	}
+	buf.WriteString("\n")
+
+	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write .env.example: %w", err)
+	}
+	fmt.Println("----- .env.example -----")
+	fmt.Print(buf.String())
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return outPath, nil
+}
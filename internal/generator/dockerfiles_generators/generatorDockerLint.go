@@ -0,0 +1,54 @@
+package dockerfiles_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/catalog"
+	"github.com/Dancoi/gogen-self-deploy/internal/dockerlint"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// LintDockerfile проверяет Dockerfile, которым собирается образ модуля языка lang — сгенерированный
+// gentmp/Dockerfile или свой из репозитория, — печатает находки с подсказками и сохраняет отчёт
+// GitLab Code Quality в gentmp/dockerfile-lint.json (пути от корня репозитория).
+func LintDockerfile(repoRoot, lang string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	outPath := filepath.Join(tmpDir, "dockerfile-lint.json")
+
+	var docker dto.DockerMeta
+	var app dockerlint.App
+	if m := analysis.ModuleFor(lang); m != nil {
+		docker = m.Docker
+		app = dockerlint.App{Port: m.Port(), HealthPath: m.HealthPath}
+	}
+	src := docker.Dockerfile()
+	if docker.DockerfilePath != "" {
+		src = filepath.Join(repoRoot, src)
+	}
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", docker.Dockerfile(), err)
+	}
+	// без каталога образов линтер работает, только не подсказывает теги
+	cat, _ := catalog.Load()
+	findings := dockerlint.Lint(docker.Dockerfile(), content, cat, app)
+
+	var text, report bytes.Buffer
+	if err := dockerlint.Write(&text, findings, dockerlint.FormatText); err != nil {
+		return "", err
+	}
+	if err := dockerlint.Write(&report, findings, dockerlint.FormatCodeQuality); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(outPath, report.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write dockerfile lint report: %w", err)
+	}
+	fmt.Println("----- dockerfile lint -----")
+	fmt.Print(text.String())
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return outPath, nil
+}
@@ -0,0 +1,394 @@
+package dockerfiles_generators
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// dockerignoreData — данные шаблонов templates/dockerignore для одного модуля.
+type dockerignoreData struct {
+	Dir       string   // каталог модуля относительно контекста со слешем, "" — корень
+	Framework string   // как в анализе: "Next.js", "Django", "Rails"
+	Outputs   []string // каталоги сборки модуля, которые пересобираются в образе
+}
+
+// GenerateDockerignore пишет gentmp/Dockerfile.dockerignore: BuildKit берёт его для сборок
+// с -f gentmp/Dockerfile вместо .dockerignore в корне контекста. Правила — шаблоны
+// templates/dockerignore по языкам и фреймворкам модулей, затем .dockerignore и .gitignore
+// репозитория. Файлы, которые Dockerfile копирует явно (COPY go.mod, COPY gradle ...),
+// и манифесты модулей возвращаются в контекст исключениями "!". Если образ собирается
+// Dockerfile'ом из репозитория (модуль языка lang, как в useRepoDockerfile), файл не пишется.
+func GenerateDockerignore(repoRoot, lang string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	outPath := filepath.Join(tmpDir, "Dockerfile.dockerignore")
+	dockerfile, err := os.ReadFile(filepath.Join(tmpDir, "Dockerfile"))
+	if errors.Is(err, fs.ErrNotExist) {
+		if m := analysis.ModuleFor(lang); m != nil && m.Docker.DockerfilePath != "" {
+			// свой Dockerfile собирается на месте: действует .dockerignore его контекста, репозиторий не трогаем
+			fmt.Printf("Skipping Dockerfile.dockerignore: %s uses %s or %s.dockerignore\n",
+				m.Docker.Dockerfile(), path.Join(m.Docker.Context(), ".dockerignore"), m.Docker.Dockerfile())
+			return "", nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("read gentmp/Dockerfile: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Dockerfile.dockerignore — сгенерировано gogen-self-deploy\n")
+	buf.WriteString("# BuildKit применяет его к контексту сборки docker build -f gentmp/Dockerfile .\n")
+
+	seen := map[string]bool{}
+	var patterns []string
+	section := func(title string, lines []string) {
+		var fresh []string
+		for _, l := range lines {
+			if !seen[l] {
+				seen[l] = true
+				fresh = append(fresh, l)
+			}
+		}
+		if len(fresh) == 0 {
+			return
+		}
+		buf.WriteString("\n# " + title + "\n")
+		for _, l := range fresh {
+			buf.WriteString(l + "\n")
+		}
+		patterns = append(patterns, fresh...)
+	}
+
+	common, err := renderDockerignore("common", dockerignoreData{})
+	if err != nil {
+		return "", err
+	}
+	section("common", common)
+
+	var manifests []string
+	gitignoreDirs := []string{""}
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			lang := m.CanonicalLanguage()
+			if lang == "" || lang == "unknown" {
+				continue
+			}
+			data := dockerignoreData{Dir: moduleContextDir(repoRoot, m), Framework: m.Framework}
+			data.Outputs = moduleOutputs(data.Dir, m, analysis)
+			lines, err := renderDockerignore(lang, data)
+			if err != nil {
+				return "", err
+			}
+			title := lang + ": " + m.Name
+			if data.Dir != "" {
+				title += " (" + strings.TrimSuffix(data.Dir, "/") + ")"
+			}
+			section(title, lines)
+			if rel, err := filepath.Rel(repoRoot, m.ModulePath); err == nil && !strings.HasPrefix(rel, "..") {
+				manifests = append(manifests, filepath.ToSlash(rel))
+			}
+			if data.Dir != "" && !containsString(gitignoreDirs, data.Dir) {
+				gitignoreDirs = append(gitignoreDirs, data.Dir)
+			}
+		}
+	}
+
+	if lines := readIgnoreFile(filepath.Join(repoRoot, ".dockerignore")); len(lines) > 0 {
+		section(".dockerignore", lines)
+	}
+	for _, dir := range gitignoreDirs {
+		var gitignore []string
+		for _, l := range readIgnoreFile(filepath.Join(repoRoot, filepath.FromSlash(dir), ".gitignore")) {
+			if p, ok := gitignoreToDockerignore(l, dir); ok {
+				gitignore = append(gitignore, p)
+			}
+		}
+		section(dir+".gitignore", gitignore)
+	}
+
+	// Всё, что Dockerfile копирует по имени, должно остаться в контексте
+	matcher := newIgnoreMatcher(patterns)
+	var keep []string
+	for _, src := range append(dockerfileCopySources(dockerfile), manifests...) {
+		for _, p := range contextPaths(repoRoot, src) {
+			if matcher.excluded(p) {
+				keep = append(keep, "!"+p)
+				matcher.keep(p)
+			}
+		}
+	}
+	section("копируется Dockerfile", keep)
+
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir gentmp: %w", err)
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write Dockerfile.dockerignore: %w", err)
+	}
+	fmt.Println("----- Dockerfile.dockerignore -----")
+	fmt.Print(buf.String())
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return outPath, nil
+}
+
+func renderDockerignore(name string, data dockerignoreData) ([]string, error) {
+	tplPath := filepath.Join("templates", "dockerignore", name+".dockerignore.tmpl")
+	raw, err := os.ReadFile(tplPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read dockerignore template: %w", err)
+	}
+	tpl, err := template.New(name).Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse dockerignore template %s: %w", tplPath, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render dockerignore template %s: %w", tplPath, err)
+	}
+	var lines []string
+	for _, l := range strings.Split(buf.String(), "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, "#") {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+// moduleContextDir — каталог модуля относительно корня репозитория со слешем, "" для корня.
+func moduleContextDir(repoRoot string, m *dto.AnalyzeDTO) string {
+	rel, err := filepath.Rel(repoRoot, filepath.Dir(m.ModulePath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel) + "/"
+}
+
+// moduleOutputs: target/ или build/ каждого модуля сборки Java, каталог сборки Node-модуля.
+func moduleOutputs(dir string, m *dto.AnalyzeDTO, analysis *dto.ProjectDTO) []string {
+	switch m.CanonicalLanguage() {
+	case dto.LangJava:
+		out := "target"
+		if m.BuildTool == dto.BuildGradle {
+			out = "build"
+		}
+		if graph := analysis.BuildGraphFor(m); graph != nil {
+			root := strings.Trim(graph.Root, "/")
+			var dirs []string
+			for _, n := range graph.Nodes {
+				dirs = append(dirs, path.Join(root, n.Path, out))
+			}
+			return dirs
+		}
+		return []string{dir + out}
+	case dto.LangNode:
+		if m.Node == nil || !m.Node.BuildScript || m.ArtifactPath == "" {
+			// без скрипта build собранные файлы берутся из репозитория как есть
+			return nil
+		}
+		first := strings.SplitN(strings.TrimPrefix(m.ArtifactPath, "./"), "/", 2)[0]
+		if first == "" || first == "." {
+			return nil
+		}
+		return []string{dir + first}
+	}
+	return nil
+}
+
+// readIgnoreFile возвращает значимые строки .gitignore/.dockerignore без комментариев.
+func readIgnoreFile(p string) []string {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		l := strings.TrimSpace(sc.Text())
+		if l != "" && !strings.HasPrefix(l, "#") {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// gitignoreToDockerignore переводит правило .gitignore из каталога base в синтаксис .dockerignore:
+// правило без слеша в gitignore действует на любой глубине, в dockerignore — только от корня контекста.
+func gitignoreToDockerignore(line, base string) (string, bool) {
+	neg := strings.HasPrefix(line, "!")
+	p := strings.TrimPrefix(line, "!")
+	p = strings.TrimPrefix(p, `\`)
+	p = strings.TrimSuffix(p, "/")
+	if p == "" || p == "/" {
+		return "", false
+	}
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if !anchored && !strings.HasPrefix(p, "**/") {
+		p = "**/" + p
+	}
+	if base != "" {
+		p = strings.TrimSuffix(base, "/") + "/" + p
+	}
+	if neg {
+		p = "!" + p
+	}
+	return p, true
+}
+
+// dockerfileCopySources — источники COPY/ADD из контекста сборки; --from, heredoc и URL пропускаются,
+// как и сам контекст ("." целиком).
+func dockerfileCopySources(dockerfile []byte) []string {
+	var out []string
+	text := strings.ReplaceAll(string(dockerfile), "\\\n", " ")
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.TrimSpace(line))
+		if len(fields) < 3 {
+			continue
+		}
+		if instr := strings.ToUpper(fields[0]); instr != "COPY" && instr != "ADD" {
+			continue
+		}
+		args := fields[1:]
+		fromStage := false
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			if strings.HasPrefix(args[0], "--from=") {
+				fromStage = true
+			}
+			args = args[1:]
+		}
+		if fromStage || len(args) < 2 || strings.HasPrefix(args[0], "<<") {
+			continue
+		}
+		if strings.HasPrefix(args[0], "[") {
+			joined := strings.Trim(strings.Join(args, " "), "[]")
+			args = args[:0]
+			for _, a := range strings.Split(joined, ",") {
+				args = append(args, strings.Trim(strings.TrimSpace(a), `"`))
+			}
+		}
+		for _, src := range args[:len(args)-1] {
+			src = path.Clean(strings.TrimPrefix(src, "./"))
+			if src == "." || strings.HasPrefix(src, "..") || strings.Contains(src, "://") || strings.Contains(src, "$") {
+				continue
+			}
+			out = append(out, src)
+		}
+	}
+	return out
+}
+
+// contextPaths раскрывает источник COPY (с масками) в пути репозитория. Каталоги целиком
+// не обходятся: исключения внутри скопированного каталога (target/, node_modules) остаются в силе.
+func contextPaths(repoRoot, src string) []string {
+	matches, _ := filepath.Glob(filepath.Join(repoRoot, filepath.FromSlash(src)))
+	out := make([]string, 0, len(matches))
+	for _, abs := range matches {
+		if rel, err := filepath.Rel(repoRoot, abs); err == nil && rel != "." {
+			out = append(out, filepath.ToSlash(rel))
+		}
+	}
+	return out
+}
+
+// ignoreMatcher повторяет семантику .dockerignore: правило действует и на вложенные пути,
+// побеждает последнее совпавшее, "!" возвращает путь в контекст.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re  *regexp.Regexp
+	neg bool
+}
+
+func newIgnoreMatcher(patterns []string) *ignoreMatcher {
+	m := &ignoreMatcher{}
+	for _, p := range patterns {
+		neg := strings.HasPrefix(p, "!")
+		p = path.Clean(strings.TrimPrefix(strings.TrimPrefix(p, "!"), "/"))
+		re, err := regexp.Compile(ignorePatternRegexp(p))
+		if err != nil {
+			continue
+		}
+		m.rules = append(m.rules, ignoreRule{re: re, neg: neg})
+	}
+	return m
+}
+
+// keep добавляет исключение "!p" после всех правил.
+func (m *ignoreMatcher) keep(p string) {
+	m.rules = append(m.rules, ignoreRule{re: regexp.MustCompile(ignorePatternRegexp(p)), neg: true})
+}
+
+func (m *ignoreMatcher) excluded(p string) bool {
+	parts := strings.Split(p, "/")
+	excluded := false
+	for _, r := range m.rules {
+		for i := 1; i <= len(parts); i++ {
+			if r.re.MatchString(strings.Join(parts[:i], "/")) {
+				excluded = !r.neg
+				break
+			}
+		}
+	}
+	return excluded
+}
+
+// ignorePatternRegexp: "**" — любое число каталогов, "*" и "?" — в пределах одного сегмента.
+func ignorePatternRegexp(p string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			if j := strings.IndexByte(p[i:], ']'); j > 0 {
+				b.WriteString(p[i : i+j+1])
+				i += j
+			} else {
+				b.WriteString(`\[`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -8,14 +8,14 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 )
 
 // GenerateGoDockerfile рендерит мультистейдж Dockerfile из шаблона
 // templates/dockerfiles/go/alpine/Dockerfile_go_multistage.tmpl
-// и сохраняет его в gentmp/Dockerfile. Если в репозитории уже есть Dockerfile,
-// копирует его вместо рендера. Выводит содержимое в консоль.
-func GenerateGoDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisResult) (string, error) {
+// и сохраняет его в gentmp/Dockerfile. Если анализатор нашёл Dockerfile модуля в репозитории,
+// возвращает его путь вместо рендера. Выводит содержимое в консоль.
+func GenerateGoDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
 	// 1) Целевая папка
 	tmpDir := "gentmp"
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
@@ -23,21 +23,9 @@ func GenerateGoDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisRes
 	}
 	outPath := filepath.Join(tmpDir, "Dockerfile")
 
-	// 2) Если Dockerfile уже есть в репо — копируем
-	existing := filepath.Join(repoRoot, "Dockerfile")
-	if fi, err := os.Stat(existing); err == nil && !fi.IsDir() {
-		b, err := os.ReadFile(existing)
-		if err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(outPath, b, 0o644); err != nil {
-			return "", err
-		}
-		fmt.Println("Saved Dockerfile to:", outPath)
-		fmt.Println("----- Dockerfile -----")
-		fmt.Println(string(b))
-		fmt.Println("----- end -----")
-		return outPath, nil
+	// 2) Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangGo); ok {
+		return path, nil
 	}
 
 	// 3) Иначе рендерим из шаблона мультистейдж
@@ -63,31 +51,50 @@ func GenerateGoDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisRes
 	// 4) Данные
 	goVersion := "1.22"
 	binaryName := "app"
+	buildTarget := "."
 	appPort := ""
+	var module *dto.AnalyzeDTO
 	if analysis != nil && len(analysis.Modules) > 0 {
 		m := analysis.Modules[0]
+		module = m
 		if v := strings.TrimSpace(m.LanguageVersion); v != "" {
-			goVersion = v
+			goVersion = goMinorVersion(v)
 		}
 		rawName := strings.TrimSpace(m.Name)
 		if rawName != "" {
 			parts := strings.Split(rawName, "/")
 			binaryName = sanitizeBinaryName(parts[len(parts)-1])
 		}
-		appPort = strings.TrimSpace(m.AppPort)
+		if t, ok := chooseGoBuildTarget(m.BuildTargets, binaryName); ok {
+			binaryName = sanitizeBinaryName(t.Name)
+			buildTarget = t.Path
+		}
+		appPort = m.Port()
+	}
+	// Статический бинарник (CGO_ENABLED=0) запускается и без shell: в distroless или scratch
+	runtimeImage, flavor := "alpine:3.20", runtimeFlavor{distro: distroAlpine, probe: probeWget}
+	switch shellFreeBase(analysis, dto.LangGo, dto.BaseDistroless, dto.BaseScratch) {
+	case dto.BaseDistroless:
+		runtimeImage, flavor = "gcr.io/distroless/static-debian12:nonroot", runtimeFlavor{}
+	case dto.BaseScratch:
+		runtimeImage, flavor = "scratch", runtimeFlavor{}
 	}
 	data := map[string]any{
 		"GoVersion":        goVersion,
 		"BaseImageBuilder": fmt.Sprintf("golang:%s-alpine", goVersion),
-		"BaseImageRuntime": "alpine:3.20",
+		"BaseImageRuntime": runtimeImage,
 		"AppWorkdir":       "/app",
 		"BinaryName":       binaryName,
+		"BuildTarget":      buildTarget,
 		"CGOEnabled":       "0",
 		"ExposePort":       appPort,
 		"RunTests":         false,
 		"LdFlags":          "",
 		"Env":              map[string]string{},
 		"BuildArgs":        map[string]string{},
+		"AirVersion":       "v1.52.3",
+		"DelveVersion":     "v1.23.1",
+		"Hardening":        newHardening(analysis, module, appPort, flavor),
 	}
 
 	var buf bytes.Buffer
@@ -105,6 +112,20 @@ func GenerateGoDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisRes
 	return outPath, nil
 }
 
+// chooseGoBuildTarget выбирает бинарник для образа по умолчанию: одноимённый модулю,
+// иначе первый найденный. Остальные собираются через --build-arg BUILD_TARGET.
+func chooseGoBuildTarget(targets []dto.BuildTarget, preferred string) (dto.BuildTarget, bool) {
+	if len(targets) == 0 {
+		return dto.BuildTarget{}, false
+	}
+	for _, t := range targets {
+		if sanitizeBinaryName(t.Name) == preferred {
+			return t, true
+		}
+	}
+	return targets[0], true
+}
+
 func sanitizeBinaryName(name string) string {
 	s := strings.ToLower(name)
 	var b strings.Builder
@@ -117,3 +138,19 @@ func sanitizeBinaryName(name string) string {
 	}
 	return strings.Trim(b.String(), "-")
 }
+
+// goMinorVersion: "1.22.3" -> "1.22", "1.21rc2" -> "1.21".
+func goMinorVersion(v string) string {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(v), "go"), ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	minor := parts[1]
+	for i := 0; i < len(minor); i++ {
+		if minor[i] < '0' || minor[i] > '9' {
+			minor = minor[:i]
+			break
+		}
+	}
+	return parts[0] + "." + minor
+}
@@ -0,0 +1,168 @@
+package dockerfiles_generators
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// HardenedUID — UID и GID пользователя усиленного профиля. Числом, а не именем: USER работает
+// в distroless и scratch без /etc/passwd, а runAsNonRoot в k8s может проверить образ до запуска.
+const HardenedUID = 10001
+
+// ParseHardening разбирает значение --hardened: true|false|distroless|scratch.
+func ParseHardening(s string) (dto.Hardening, error) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "", "false", "off", "no":
+		return dto.Hardening{}, nil
+	case "true", "on", "yes":
+		return dto.Hardening{Enabled: true}, nil
+	case dto.BaseDistroless, dto.BaseScratch:
+		return dto.Hardening{Enabled: true, Base: v}, nil
+	}
+	return dto.Hardening{}, fmt.Errorf("unknown --hardened value %q (want true, false, distroless or scratch)", s)
+}
+
+// hardening — данные шаблонов Dockerfile под ключом "Hardening"; нулевое значение — профиль выключен.
+type hardening struct {
+	Enabled     bool
+	User        string // "10001:10001" для USER и владельца файлов
+	Chown       string // "--chown=10001:10001 " перед аргументами COPY
+	CreateUser  string // команда RUN, создающая пользователя; "" — образ без shell или пользователь не нужен в /etc/passwd
+	StopSignal  string
+	Healthcheck string // инструкция HEALTHCHECK или комментарий, почему её нет
+}
+
+// Чем в рантайм-образе создать пользователя.
+const (
+	distroAlpine = "alpine" // busybox addgroup/adduser
+	distroDebian = "debian" // shadow groupadd/useradd
+)
+
+// Чем проверить HTTP-эндпоинт изнутри контейнера, не полагаясь на shell.
+const (
+	probeWget   = "wget" // busybox в alpine
+	probeCurl   = "curl"
+	probeNode   = "node"
+	probePython = "python"
+	probeRuby   = "ruby"
+)
+
+// runtimeFlavor — устройство рантайм-образа для усиленного профиля.
+type runtimeFlavor struct {
+	distro      string // distroAlpine|distroDebian; "" — пользователь не создаётся
+	probe       string // "" — нечем выполнить HEALTHCHECK (distroless, scratch)
+	stopSignal  string // "" — SIGTERM; nginx и php-fpm завершаются корректно по SIGQUIT
+	startPeriod string // "" — 10s; JVM стартует дольше
+}
+
+// newHardening собирает данные шаблона для модуля m, слушающего port; без --hardened — нулевое значение.
+func newHardening(analysis *dto.ProjectDTO, m *dto.AnalyzeDTO, port string, flavor runtimeFlavor) hardening {
+	if analysis == nil || !analysis.Options.Hardening.Enabled {
+		return hardening{}
+	}
+	user := fmt.Sprintf("%d:%d", HardenedUID, HardenedUID)
+	h := hardening{
+		Enabled:     true,
+		User:        user,
+		Chown:       "--chown=" + user + " ",
+		StopSignal:  cmp.Or(flavor.stopSignal, "SIGTERM"),
+		Healthcheck: healthcheckInstruction(m, port, flavor),
+	}
+	switch flavor.distro {
+	case distroAlpine:
+		h.CreateUser = fmt.Sprintf("addgroup -S -g %[1]d app && adduser -S -D -H -u %[1]d -G app app", HardenedUID)
+	case distroDebian:
+		h.CreateUser = fmt.Sprintf("groupadd --system --gid %[1]d app && useradd --system --uid %[1]d --gid app --no-create-home app", HardenedUID)
+	}
+	return h
+}
+
+// healthcheckInstruction — HEALTHCHECK в exec-форме против найденного анализатором эндпоинта.
+func healthcheckInstruction(m *dto.AnalyzeDTO, port string, flavor runtimeFlavor) string {
+	route := ""
+	if m != nil {
+		route = m.HealthPath
+		if m.StaticSite() {
+			route = "/"
+		}
+	}
+	switch {
+	case port == "":
+		return "# No HEALTHCHECK: the application port is unknown"
+	case route == "":
+		return "# No HEALTHCHECK: no health endpoint detected (/healthz, /health, /actuator/health); expose one to get a check"
+	case flavor.probe == "":
+		return "# No HEALTHCHECK: the image has no shell or HTTP client; the orchestrator probes " + route
+	}
+	url := "http://127.0.0.1:" + port + route
+	return fmt.Sprintf("HEALTHCHECK --interval=30s --timeout=3s --start-period=%s --retries=3 \\\n    CMD %s",
+		cmp.Or(flavor.startPeriod, "10s"), execForm(probeCommand(flavor.probe, url)))
+}
+
+func probeCommand(probe, url string) []string {
+	switch probe {
+	case probeCurl:
+		return []string{"curl", "-fsS", "-o", "/dev/null", url}
+	case probeNode:
+		return []string{"node", "-e", "require('http').get('" + url + "', r => process.exit(r.statusCode < 400 ? 0 : 1)).on('error', () => process.exit(1))"}
+	case probePython:
+		return []string{"python", "-c", "import urllib.request; urllib.request.urlopen('" + url + "', timeout=3)"}
+	case probeRuby:
+		return []string{"ruby", "-rnet/http", "-e", "exit Net::HTTP.get_response(URI('" + url + "')).is_a?(Net::HTTPSuccess)"}
+	}
+	return []string{"wget", "-q", "-O", "/dev/null", url}
+}
+
+// execForm — JSON-массив аргументов для CMD/ENTRYPOINT/HEALTHCHECK без shell.
+func execForm(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = strconv.Quote(a)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// shellFreeBase — выбранный через --hardened=distroless|scratch вариант рантайма без shell, если генератор
+// языка его умеет; иначе предупреждает и возвращает "" — образ остаётся обычным.
+func shellFreeBase(analysis *dto.ProjectDTO, lang string, supported ...string) string {
+	if analysis == nil || !analysis.Options.Hardening.Enabled {
+		return ""
+	}
+	base := analysis.Options.Hardening.Base
+	if base == "" || slices.Contains(supported, base) {
+		return base
+	}
+	fmt.Printf("Warning: no %s runtime for %s images, keeping the default base with the hardened profile\n", base, lang)
+	return ""
+}
+
+// WritablePaths — каталоги, куда пишет рантайм сгенерированного образа при read-only корневой ФС:
+// compose монтирует их как tmpfs, k8s — как emptyDir. Для Dockerfile репозитория известен только /tmp.
+func WritablePaths(m *dto.AnalyzeDTO) []string {
+	paths := []string{"/tmp"}
+	if m == nil || m.Docker.DockerfilePath != "" {
+		return paths
+	}
+	switch {
+	case m.CanonicalLanguage() == dto.LangPHP:
+		// nginx из alpine: pid, буферы запросов и логи
+		paths = append(paths, "/run/nginx", "/var/lib/nginx/tmp", "/var/log/nginx")
+	case m.CanonicalLanguage() == dto.LangRuby:
+		// pid-файл puma и кеш bootsnap
+		paths = append(paths, "/app/tmp")
+	case m.Framework == "Next.js":
+		// кеш оптимизации изображений и ISR
+		dir := m.ReactorModule
+		if m.ArtifactPath == ".next/standalone" {
+			dir = strings.TrimSuffix(strings.TrimPrefix(m.StartCommand, "node "), "server.js")
+		}
+		paths = append(paths, path.Join("/app", dir, ".next/cache"))
+	}
+	return paths
+}
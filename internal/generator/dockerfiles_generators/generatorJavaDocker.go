@@ -8,56 +8,61 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/util"
 )
 
-// GenerateJavaDockerfile генерирует или копирует мультистейдж Dockerfile для Java.
+// GenerateJavaDockerfile генерирует мультистейдж Dockerfile для Java.
 // Определяет инструмент сборки (Maven / Gradle) и берёт соответствующий шаблон:
 // Maven: templates/dockerfiles/java/maven/distroless/Dockerfile_java_maven_multistage.tmpl
 // Gradle: templates/dockerfiles/java/gradle/distroless/Dockerfile_java_gradle_multistage.tmpl
-// Сохраняет результат в gentmp/Dockerfile.
-func GenerateJavaDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisResult) (string, error) {
+// Сохраняет результат в gentmp/Dockerfile; Dockerfile модуля из репозитория не копируется — возвращается его путь.
+func GenerateJavaDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
 	tmpDir := "gentmp"
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
 		return "", fmt.Errorf("mkdir gentmp: %w", err)
 	}
 	outPath := filepath.Join(tmpDir, "Dockerfile")
 
-	// 1) Существующий Dockerfile
-	existing := filepath.Join(repoRoot, "Dockerfile")
-	if fi, err := os.Stat(existing); err == nil && !fi.IsDir() {
-		b, err := os.ReadFile(existing)
-		if err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(outPath, b, 0o644); err != nil {
-			return "", err
-		}
-		util.PrintDockerfile(outPath, b)
-		return outPath, nil
+	// 1) Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangJava); ok {
+		return path, nil
 	}
 
 	// 2) Анализ модуля Java
 	buildTool := "maven"
 	javaVersion := "17"
-	var module *analyzer.ProjectModule
+	appPort := ""
+	sourceDir, mavenModule, jarPath := "", "", ""
+	pomFiles, buildFiles, entrypoint := []string{}, []string{}, []string{}
+	gradleTasks, distPath := "", ""
+	var module *dto.AnalyzeDTO
 	if analysis != nil {
 		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguageJava {
+			if m.Language == dto.LangJava {
 				module = m
 				break
 			}
 		}
 	}
 	if module != nil {
-		bt := strings.ToLower(string(module.BuildTool))
+		bt := strings.ToLower(module.BuildTool)
 		if strings.Contains(bt, "gradle") {
 			buildTool = "gradle"
 		}
 		if v := strings.TrimSpace(module.LanguageVersion); v != "" {
 			javaVersion = trimJavaVersion(v)
 		}
+		appPort = module.Port()
+		if buildTool == "maven" {
+			sourceDir, mavenModule, pomFiles = mavenReactorLayout(module, analysis)
+			if mavenModule != "" {
+				jarPath = "/app/" + mavenModule + "/target/*.jar"
+			}
+		} else {
+			sourceDir, buildFiles, gradleTasks = gradleBuildLayout(repoRoot, module, analysis)
+			jarPath, distPath, entrypoint = gradleArtifact(module, sourceDir)
+		}
 	}
 
 	var tplPath string
@@ -84,17 +89,51 @@ func GenerateJavaDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisR
 		return "", fmt.Errorf("parse java dockerfile template: %w", err)
 	}
 
+	// в образе eclipse-temurin нет mvn/gradle — собираем в официальных образах инструмента той же версии JDK
+	builderImage, builderRepo := fmt.Sprintf("maven:3.9-eclipse-temurin-%s", majorJava(javaVersion)), "maven:"
+	if buildTool == "gradle" {
+		builderImage, builderRepo = fmt.Sprintf("gradle:8.5-jdk%s", majorJava(javaVersion)), "gradle:"
+	}
+	if module != nil && strings.HasPrefix(module.BuilderImage, builderRepo) {
+		builderImage = module.BuilderImage
+	}
+	// JVM в distroless запускается без shell; скрипт installDist (bin/<app>) без него не работает.
+	// Образ рантайма — тот же, что в анализе (runtime_image), иначе отчёт и Dockerfile расходятся
+	runtimeImage := fmt.Sprintf("eclipse-temurin:%s-jre", majorJava(javaVersion))
+	if module != nil && strings.HasPrefix(module.RuntimeImage, "eclipse-temurin:") {
+		runtimeImage = module.RuntimeImage
+	}
+	flavor := runtimeFlavor{distro: distroDebian, probe: probeCurl, startPeriod: "60s"}
+	distroless := fmt.Sprintf("gcr.io/distroless/java%s-debian12", majorJava(javaVersion))
+	if len(entrypoint) > 0 && strings.HasPrefix(entrypoint[0], "/app/bin/") {
+		if base := analysis.Options.Hardening.Base; base != "" {
+			fmt.Printf("Warning: %s runtime cannot run the installDist start script, keeping %s\n", base, runtimeImage)
+		}
+	} else if shellFreeBase(analysis, dto.LangJava, dto.BaseDistroless) != "" {
+		runtimeImage, flavor = distroless+":nonroot", runtimeFlavor{}
+	}
 	data := map[string]any{
 		"JavaVersion":       javaVersion,
 		"AppWorkdir":        "/app",
 		"JarNamePattern":    "*.jar",
 		"BuildTool":         buildTool,
-		"RuntimeBaseImage":  fmt.Sprintf("gcr.io/distroless/java%s-debian12", majorJava(javaVersion)),
-		"BaseImageBuilder":  fmt.Sprintf("eclipse-temurin:%s-jdk", majorJava(javaVersion)),
-		"BaseImageRuntime":  fmt.Sprintf("eclipse-temurin:%s-jre", majorJava(javaVersion)),
+		"RuntimeBaseImage":  distroless,
+		"BaseImageBuilder":  builderImage,
+		"BaseImageRuntime":  runtimeImage,
 		"MainClass":         "",
 		"AdditionalRunArgs": []string{},
 		"SkipTests":         "true",
+		"ExposePort":        appPort,
+		"SourceDir":         sourceDir,
+		"MavenModule":       mavenModule,
+		"PomFiles":          pomFiles,
+		"ProjectJarPath":    jarPath,
+		"JarOutputPath":     jarPath,
+		"BuildFiles":        buildFiles,
+		"GradleTasks":       gradleTasks,
+		"DistPath":          distPath,
+		"Entrypoint":        entrypoint,
+		"Hardening":         newHardening(analysis, module, appPort, flavor),
 	}
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, data); err != nil {
@@ -107,6 +146,83 @@ func GenerateJavaDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisR
 	return outPath, nil
 }
 
+// mavenReactorLayout возвращает каталог сборки относительно контекста ("backend/" или ""),
+// модуль для -pl и pom.xml всех модулей reactor-а — их копируем до исходников ради кеша зависимостей.
+func mavenReactorLayout(module *dto.AnalyzeDTO, analysis *dto.ProjectDTO) (string, string, []string) {
+	sourceDir := ""
+	if root := strings.Trim(module.BuildRoot, "/"); root != "" && root != "." {
+		sourceDir = root + "/"
+	}
+	graph := analysis.BuildGraphFor(module)
+	if graph == nil {
+		return sourceDir, "", []string{}
+	}
+	poms := make([]string, 0, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		if n.Path == "." {
+			poms = append(poms, "pom.xml")
+		} else {
+			poms = append(poms, n.Path+"/pom.xml")
+		}
+	}
+	return sourceDir, module.ReactorModule, poms
+}
+
+// gradleBuildLayout возвращает каталог сборки относительно контекста, существующие build-скрипты
+// (settings, build.gradle подпроектов, gradle.properties, gradle/ с wrapper и version catalog) и задачу сборки.
+func gradleBuildLayout(repoRoot string, module *dto.AnalyzeDTO, analysis *dto.ProjectDTO) (string, []string, string) {
+	sourceDir := ""
+	if root := strings.Trim(module.BuildRoot, "/"); root != "" && root != "." {
+		sourceDir = root + "/"
+	}
+	base := filepath.Join(repoRoot, filepath.FromSlash(sourceDir))
+
+	dirs := []string{"."}
+	if graph := analysis.BuildGraphFor(module); graph != nil {
+		dirs = dirs[:0]
+		for _, n := range graph.Nodes {
+			dirs = append(dirs, n.Path)
+		}
+	}
+	var files []string
+	for _, name := range []string{"settings.gradle.kts", "settings.gradle", "gradle.properties", "gradle"} {
+		if _, err := os.Stat(filepath.Join(base, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	for _, dir := range dirs {
+		for _, name := range []string{"build.gradle.kts", "build.gradle"} {
+			rel := filepath.ToSlash(filepath.Join(dir, name))
+			if _, err := os.Stat(filepath.Join(base, filepath.FromSlash(rel))); err == nil {
+				files = append(files, rel)
+			}
+		}
+	}
+
+	task := strings.TrimSpace(module.BuildTask)
+	if task == "" {
+		task = "build"
+	}
+	return sourceDir, files, task
+}
+
+// gradleArtifact: путь к jar в builder-стейдже либо каталог дистрибутива (installDist / quarkus-app)
+// вместе с точкой входа для него.
+func gradleArtifact(module *dto.AnalyzeDTO, sourceDir string) (string, string, []string) {
+	rel := strings.TrimPrefix(strings.TrimPrefix(module.ArtifactPath, "./"), sourceDir)
+	if rel == "" {
+		return "", "", []string{}
+	}
+	switch {
+	case strings.HasSuffix(module.BuildTask, "installDist"):
+		name := filepath.Base(strings.TrimSuffix(rel, "/"))
+		return "", "/app/" + rel, []string{"/app/bin/" + name}
+	case strings.HasSuffix(module.BuildTask, "quarkusBuild"):
+		return "", "/app/" + rel, []string{"java", "-jar", "/app/quarkus-run.jar"}
+	}
+	return "/app/" + rel, "", []string{}
+}
+
 func trimJavaVersion(v string) string {
 	v = strings.TrimSpace(v)
 	v = strings.TrimPrefix(strings.ToLower(v), "java")
@@ -2,6 +2,7 @@ package dockerfiles_generators
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,47 +11,34 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/util"
 )
 
-func GenerateNodeDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisResult) (string, error) {
+func GenerateNodeDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
 	tmpDir := "gentmp"
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
 		return "", fmt.Errorf("mkdir gentmp: %w", err)
 	}
 	outPath := filepath.Join(tmpDir, "Dockerfile")
 
-	existing := filepath.Join(repoRoot, "Dockerfile")
-	if fi, err := os.Stat(existing); err == nil && !fi.IsDir() {
-		b, err := os.ReadFile(existing)
-		if err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(outPath, b, 0o644); err != nil {
-			return "", err
-		}
-		util.PrintDockerfile(outPath, b)
-		return outPath, nil
+	// Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangNode); ok {
+		return path, nil
 	}
 
-	tplPath := filepath.Join("templates", "dockerfiles", "node", "alpine", "Dockerfile_node_multistage.tmpl")
-	raw, err := os.ReadFile(tplPath)
-	if err != nil {
-		return "", fmt.Errorf("read node dockerfile template: %w", err)
-	}
-	funcs := template.FuncMap{
-		"default": func(def string, val string) string {
-			if strings.TrimSpace(val) == "" {
-				return def
+	var primary *dto.AnalyzeDTO
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			if m.CanonicalLanguage() == dto.LangNode {
+				primary = m
+				break
 			}
-			return val
-		},
-		"upper": strings.ToUpper,
+		}
 	}
-	tpl, err := template.New("node-dockerfile").Funcs(funcs).Option("missingkey=zero").Parse(string(raw))
-	if err != nil {
-		return "", fmt.Errorf("parse node dockerfile template: %w", err)
+	tplPath := filepath.Join("templates", "dockerfiles", "node", "alpine", "Dockerfile_node_multistage.tmpl")
+	if path := nodeFrameworkTemplate(primary); path != "" {
+		tplPath = path
 	}
 
 	// 3) Данные анализа
@@ -61,11 +49,11 @@ func GenerateNodeDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisR
 	useDistRuntime := true
 	if analysis != nil && len(analysis.Modules) > 0 {
 		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguageJavaScript || m.Language == analyzer.LanguageTypeScript {
+			if m.CanonicalLanguage() == dto.LangNode {
 				if v := strings.TrimSpace(m.LanguageVersion); v != "" {
 					rawNodeVersion = v
 				}
-				if p := strings.TrimSpace(m.AppPort); p != "" {
+				if p := m.Port(); p != "" {
 					appPort = p
 				}
 				break
@@ -92,17 +80,106 @@ func GenerateNodeDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisR
 		"BuildScript":      buildScript,
 		"StartCommand":     startCmd,
 		"ExposePort":       appPort,
+		"DevTools":         "nodemon tsx",
+	}
+
+	if nodeFrameworkTemplate(primary) != "" {
+		data = nodeFrameworkDockerData(repoRoot, primary, nodeVersion)
 	}
 
+	// Порт по умолчанию у шаблонов фреймворков — 3000, у nginx со статикой — 8080
+	shellFreeBase(analysis, dto.LangNode)
+	port, _ := data["ExposePort"].(string)
+	flavor := runtimeFlavor{distro: distroAlpine, probe: probeNode}
+	if primary != nil {
+		switch {
+		case primary.StaticSite():
+			port, flavor = cmp.Or(port, "8080"), runtimeFlavor{probe: probeWget, stopSignal: "SIGQUIT"}
+		case nodeFrameworkTemplates[primary.Framework] != "":
+			port = cmp.Or(port, "3000")
+		}
+	}
+	data["Hardening"] = newHardening(analysis, primary, port, flavor)
+
+	if err := renderNodeDockerfile(tplPath, outPath, data); err != nil {
+		return "", err
+	}
+	return outPath, nil
+}
+
+// GenerateNodeWorkspaceDockerfile пишет gentmp/<slug>/Dockerfile для пакета workspace-а m, который
+// не попал в gentmp/Dockerfile: Next.js, NestJS, Nuxt или статический сайт. Собирается, как и основной
+// пакет, от корня workspace-а. Для пакетов без своего шаблона возвращает "" — общий multistage-шаблон
+// рассчитан на package.json в корне контекста.
+func GenerateNodeWorkspaceDockerfile(repoRoot string, analysis *dto.ProjectDTO, m *dto.AnalyzeDTO, slug string) (string, error) {
+	tplPath := nodeFrameworkTemplate(m)
+	if tplPath == "" || m.ReactorModule == "" {
+		return "", nil
+	}
+	outDir := filepath.Join("gentmp", slug)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir %s: %w", outDir, err)
+	}
+	outPath := filepath.Join(outDir, "Dockerfile")
+
+	data := nodeFrameworkDockerData(repoRoot, m, normalizeNodeVersion(m.LanguageVersion))
+	port, _ := data["ExposePort"].(string)
+	flavor := runtimeFlavor{distro: distroAlpine, probe: probeNode}
+	if m.StaticSite() {
+		port, flavor = cmp.Or(port, "8080"), runtimeFlavor{probe: probeWget, stopSignal: "SIGQUIT"}
+	} else {
+		port = cmp.Or(port, "3000")
+	}
+	data["Hardening"] = newHardening(analysis, m, port, flavor)
+
+	if err := renderNodeDockerfile(tplPath, outPath, data); err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(outPath), nil
+}
+
+// nodeFrameworkTemplate — шаблон фреймворка или nginx-шаблон статического сайта для модуля m,
+// "" — модуль собирается общим multistage-шаблоном.
+func nodeFrameworkTemplate(m *dto.AnalyzeDTO) string {
+	switch {
+	case m == nil:
+		return ""
+	case m.StaticSite():
+		return filepath.Join("templates", "dockerfiles", "node", "nginx", "Dockerfile_node_spa.tmpl")
+	case nodeFrameworkTemplates[m.Framework] != "":
+		return filepath.Join("templates", "dockerfiles", "node", "alpine", nodeFrameworkTemplates[m.Framework])
+	}
+	return ""
+}
+
+// renderNodeDockerfile рендерит шаблон tplPath в outPath и печатает результат.
+func renderNodeDockerfile(tplPath, outPath string, data map[string]any) error {
+	raw, err := os.ReadFile(tplPath)
+	if err != nil {
+		return fmt.Errorf("read node dockerfile template: %w", err)
+	}
+	funcs := template.FuncMap{
+		"default": func(def string, val string) string {
+			if strings.TrimSpace(val) == "" {
+				return def
+			}
+			return val
+		},
+		"upper": strings.ToUpper,
+	}
+	tpl, err := template.New("node-dockerfile").Funcs(funcs).Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return fmt.Errorf("parse node dockerfile template: %w", err)
+	}
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("render node dockerfile: %w", err)
+		return fmt.Errorf("render node dockerfile: %w", err)
 	}
 	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
-		return "", err
+		return err
 	}
 	util.PrintDockerfile(outPath, buf.Bytes())
-	return outPath, nil
+	return nil
 }
 
 // normalizeNodeVersion приводит сложные выражения ("20.x 22.x 24.x", ">=18 <21", "^18.17.0") к мажорной версии
@@ -154,3 +231,71 @@ func hasDistDir(root string) bool {
 	info, err := os.Stat(filepath.Join(root, "dist"))
 	return err == nil && info.IsDir()
 }
+
+// nodeFrameworkTemplates — Dockerfile-шаблоны серверных фреймворков с собственным форматом сборки.
+var nodeFrameworkTemplates = map[string]string{
+	"Next.js": "Dockerfile_node_nextjs.tmpl",
+	"NestJS":  "Dockerfile_node_nestjs.tmpl",
+	"Nuxt":    "Dockerfile_node_nuxt.tmpl",
+}
+
+// nodeFrameworkDockerData — данные для шаблонов фреймворков и статических сайтов (Dockerfile_node_spa.tmpl):
+// команды менеджера пакетов, каталог сборки, команда запуска.
+// Пакет workspace-а собирается от корня workspace-а командой оркестратора/менеджера пакетов.
+func nodeFrameworkDockerData(repoRoot string, m *dto.AnalyzeDTO, nodeVersion string) map[string]any {
+	lockfile, cacheTarget := "package-lock.json", "/root/.npm"
+	install, prodInstall, build := "npm ci", "npm ci --omit=dev", "npm run build"
+	switch m.BuildTool {
+	case dto.PmPnpm:
+		lockfile, cacheTarget = "pnpm-lock.yaml", "/root/.local/share/pnpm/store"
+		install, prodInstall, build = "pnpm install --frozen-lockfile", "pnpm install --prod --frozen-lockfile", "pnpm run build"
+	case dto.PmYarn:
+		lockfile, cacheTarget = "yarn.lock", "/usr/local/share/.cache/yarn"
+		install, prodInstall, build = "yarn install --frozen-lockfile", "yarn install --production --frozen-lockfile", "yarn build"
+	}
+
+	moduleDir := filepath.Dir(m.ModulePath)
+	packageDir := ""
+	outputDir := m.ArtifactPath
+	var manifests []string
+	if m.ReactorModule != "" {
+		build = m.BuildCommand
+		packageDir = m.ReactorModule + "/"
+		outputDir = packageDir + m.ArtifactPath
+	} else {
+		manifests = []string{"package.json"}
+		if fi, err := os.Stat(filepath.Join(repoRoot, lockfile)); err == nil && !fi.IsDir() {
+			manifests = append(manifests, lockfile)
+		} else if install == "npm ci" {
+			install, prodInstall = "npm install", "npm install --omit=dev"
+		} else {
+			install = strings.TrimSuffix(install, " --frozen-lockfile")
+			prodInstall = strings.TrimSuffix(prodInstall, " --frozen-lockfile")
+		}
+	}
+
+	runtimeImage := m.RuntimeImage
+	if !m.StaticSite() {
+		runtimeImage = fmt.Sprintf("node:%s-alpine", nodeVersion)
+	}
+	publicInfo, err := os.Stat(filepath.Join(moduleDir, "public"))
+	return map[string]any{
+		"BaseImageBuilder":   fmt.Sprintf("node:%s-alpine", nodeVersion),
+		"BaseImageRuntime":   runtimeImage,
+		"AppWorkdir":         "/app",
+		"BuildArgs":          map[string]string{},
+		"Env":                map[string]string{},
+		"ManifestFiles":      manifests,
+		"CacheTarget":        cacheTarget,
+		"InstallCommand":     install,
+		"ProdInstallCommand": prodInstall,
+		"BuildCommand":       build,
+		"PackageDir":         packageDir,
+		"OutputDir":          outputDir,
+		"Standalone":         m.ArtifactPath == ".next/standalone",
+		"StandaloneDir":      strings.TrimSuffix(strings.TrimPrefix(m.StartCommand, "node "), "server.js"),
+		"HasPublic":          err == nil && publicInfo.IsDir(),
+		"EntryFile":          strings.TrimPrefix(m.StartCommand, "node "),
+		"ExposePort":         m.Port(),
+	}
+}
@@ -0,0 +1,108 @@
+package dockerfiles_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/util"
+)
+
+// GeneratePHPDockerfile генерирует Dockerfile для PHP (Composer).
+// Шаблон: templates/dockerfiles/php/fpm/Dockerfile_php_fpm_nginx.tmpl — php-fpm и nginx в одном образе.
+// Сохраняет результат в gentmp/Dockerfile, если у модуля нет своего Dockerfile.
+func GeneratePHPDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir gentmp: %w", err)
+	}
+	outPath := filepath.Join(tmpDir, "Dockerfile")
+
+	// 1) Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangPHP); ok {
+		return path, nil
+	}
+
+	// 2) Рендер из шаблона
+	tplPath := filepath.Join("templates", "dockerfiles", "php", "fpm", "Dockerfile_php_fpm_nginx.tmpl")
+	raw, err := os.ReadFile(tplPath)
+	if err != nil {
+		return "", fmt.Errorf("read php dockerfile template: %w", err)
+	}
+	funcs := template.FuncMap{
+		"default": func(def string, val string) string {
+			if strings.TrimSpace(val) == "" {
+				return def
+			}
+			return val
+		},
+		"printf": fmt.Sprintf,
+	}
+	tpl, err := template.New("php-dockerfile").Funcs(funcs).Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse php dockerfile template: %w", err)
+	}
+
+	phpVersion := "8.2"
+	appPort := "8080"
+	documentRoot := "public"
+	var extensions []string
+	var module *dto.AnalyzeDTO
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			if m.Language != dto.LangPHP {
+				continue
+			}
+			module = m
+			if v := strings.TrimSpace(m.LanguageVersion); v != "" {
+				phpVersion = v
+			}
+			if p := m.Port(); p != "" {
+				appPort = p
+			}
+			// Laravel и Symfony работают с БД через PDO
+			if m.Framework == "Laravel" || m.Framework == "Symfony" {
+				extensions = append(extensions, "pdo_mysql", "opcache")
+			}
+			break
+		}
+	}
+	// Образ собирается из корня репозитория, но в него копируется только каталог модуля
+	// (Laravel/Symfony в монорепозитории): composer.json и public/ берутся оттуда
+	moduleDir := ""
+	if module != nil {
+		moduleDir = moduleContextDir(repoRoot, module)
+	}
+	if !dirExists(filepath.Join(repoRoot, moduleDir, "public")) {
+		documentRoot = "."
+	}
+
+	shellFreeBase(analysis, dto.LangPHP)
+	data := map[string]any{
+		"PHPVersion":       phpVersion,
+		"BaseImageBuilder": "composer:2",
+		"BaseImageRuntime": fmt.Sprintf("php:%s-fpm-alpine", phpVersion),
+		"AppWorkdir":       "/var/www/html",
+		"ModuleDir":        moduleDir,
+		"DocumentRoot":     documentRoot,
+		"PHPExtensions":    extensions,
+		"Env":              map[string]string{},
+		"BuildArgs":        map[string]string{},
+		"ExposePort":       appPort,
+		// снаружи отвечает nginx; php-fpm и nginx корректно завершаются по SIGQUIT
+		"Hardening": newHardening(analysis, module, appPort, runtimeFlavor{distro: distroAlpine, probe: probeWget, stopSignal: "SIGQUIT"}),
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render php dockerfile: %w", err)
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	util.PrintDockerfile(outPath, buf.Bytes())
+	return outPath, nil
+}
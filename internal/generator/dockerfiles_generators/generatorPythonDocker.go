@@ -8,63 +8,24 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 )
 
-// GeneratePythonDockerfile генерирует (или копирует существующий) мультистейдж Dockerfile для Python
+// GeneratePythonDockerfile генерирует мультистейдж Dockerfile для Python (или возвращает путь существующего)
 // Использует шаблон templates/dockerfiles/python/slim/Dockerfile_python_multistage.tmpl
 // Сохраняет в gentmp/Dockerfile и печатает содержимое.
-func GeneratePythonDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysisResult) (string, error) {
+func GeneratePythonDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
 	tmpDir := "gentmp"
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
 		return "", fmt.Errorf("mkdir gentmp: %w", err)
 	}
 	outPath := filepath.Join(tmpDir, "Dockerfile")
 
-	// 1) Если есть существующий Dockerfile в корне
-	existing := filepath.Join(repoRoot, "Dockerfile")
-	if fi, err := os.Stat(existing); err == nil && !fi.IsDir() {
-		b, err := os.ReadFile(existing)
-		if err != nil {
-			return "", err
-		}
-		if err := os.WriteFile(outPath, b, 0o644); err != nil {
-			return "", err
-		}
-		fmt.Println("Saved Dockerfile to:", outPath)
-		fmt.Println("----- Dockerfile -----")
-		fmt.Println(string(b))
-		fmt.Println("----- end -----")
-		return outPath, nil
-	}
-
-	// 2) Если анализатор указал путь к Dockerfile
-	if analysis != nil {
-		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguagePython && strings.TrimSpace(m.DockerfilePath) != "" {
-				abs := m.DockerfilePath
-				if !filepath.IsAbs(abs) {
-					abs = filepath.Join(repoRoot, abs)
-				}
-				if fi, err := os.Stat(abs); err == nil && !fi.IsDir() {
-					b, err := os.ReadFile(abs)
-					if err != nil {
-						return "", err
-					}
-					if err := os.WriteFile(outPath, b, 0o644); err != nil {
-						return "", err
-					}
-					fmt.Println("Saved Dockerfile to:", outPath)
-					fmt.Println("----- Dockerfile -----")
-					fmt.Println(string(b))
-					fmt.Println("----- end -----")
-					return outPath, nil
-				}
-			}
-		}
+	// 1) Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangPython); ok {
+		return path, nil
 	}
-
-	// 3) Рендер из шаблона (multistage)
+	// 2) Рендер из шаблона (multistage)
 	tplPath := filepath.Join("templates", "dockerfiles", "python", "slim", "Dockerfile_python_multistage.tmpl")
 	raw, err := os.ReadFile(tplPath)
 	if err != nil {
@@ -85,30 +46,64 @@ func GeneratePythonDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysi
 
 	pyVersion := "3.12"
 	appPort := ""
+	var primary *dto.AnalyzeDTO
 	if analysis != nil {
 		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguagePython {
+			if m.Language == dto.LangPython {
 				if v := strings.TrimSpace(m.LanguageVersion); v != "" {
 					pyVersion = v
 				}
-				if p := strings.TrimSpace(m.AppPort); p != "" {
+				if p := m.Port(); p != "" {
 					appPort = p
 				}
+				primary = m
 				break
 			}
 		}
 	}
+	hasRequirements := primary != nil && primary.Python != nil && primary.Python.HasRequirements
+	usePoetry := primary != nil && primary.BuildTool == dto.PyPoetry && !hasRequirements
+	installProject := primary != nil && primary.BuildTool == dto.PyPip && !hasRequirements && primary.Python != nil && primary.Python.HasPyproject
+	if usePoetry {
+		tplPath = filepath.Join("templates", "dockerfiles", "python", "slim", "Dockerfile_python_poetry_multistage.tmpl")
+		if raw, err = os.ReadFile(tplPath); err != nil {
+			return "", fmt.Errorf("read python dockerfile template: %w", err)
+		}
+		if tpl, err = template.New("python-dockerfile").Funcs(funcs).Option("missingkey=zero").Parse(string(raw)); err != nil {
+			return "", fmt.Errorf("parse python dockerfile template: %w", err)
+		}
+	}
+	entrypoint, serverPackages := []string{"python", "-m", "app"}, []string(nil)
+	if primary != nil && strings.TrimSpace(primary.StartCommand) != "" {
+		entrypoint, serverPackages = pythonEntrypoint(primary, usePoetry)
+	}
+	shellFreeBase(analysis, dto.LangPython)
+	hardened := newHardening(analysis, primary, appPort, runtimeFlavor{distro: distroDebian, probe: probePython})
+	if hardened.Enabled && len(entrypoint) == 3 && entrypoint[0] == "/bin/sh" {
+		// sh -c не передаёт сигнал остановки дочернему процессу
+		entrypoint = []string{"/bin/sh", "-c", "exec " + entrypoint[2]}
+	}
+	devPackages := []string{"debugpy", "watchfiles"}
+	if primary != nil && primary.Python != nil && primary.Python.Framework != "django" && primary.Python.AsgiModule != "" && !containsDependency(primary.Dependencies, "uvicorn") {
+		// docker-compose.dev.yml запускает ASGI-приложение через uvicorn --reload
+		devPackages = append(devPackages, "uvicorn[standard]")
+	}
 	data := map[string]any{
 		"BaseImageBuilder": fmt.Sprintf("python:%s-slim", pyVersion),
 		"BaseImageRuntime": fmt.Sprintf("python:%s-slim", pyVersion),
 		"AppWorkdir":       "/app",
 		"RequirementsFile": "requirements.txt",
+		"PoetryVersion":    "1.8.3",
+		"InstallProject":   installProject,
 		"UseVenv":          "true",
 		"Env":              map[string]string{},
 		"BuildArgs":        map[string]string{},
 		"RunTests":         false,
-		"Entrypoint":       []string{"python", "-m", "app"},
+		"Entrypoint":       entrypoint,
+		"ServerPackages":   serverPackages,
+		"DevPackages":      devPackages,
 		"ExposePort":       appPort,
+		"Hardening":        hardened,
 	}
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, data); err != nil {
@@ -123,3 +118,44 @@ func GeneratePythonDockerfile(repoRoot string, analysis *analyzer.ProjectAnalysi
 	fmt.Println("----- end -----")
 	return outPath, nil
 }
+
+// pythonEntrypoint превращает найденную команду запуска в ENTRYPOINT и список серверов приложений,
+// которых нет в зависимостях проекта (gunicorn/uvicorn доустанавливаются в образ).
+// Команды с переменными ($PORT из Procfile) запускаются через sh -c.
+func pythonEntrypoint(m *dto.AnalyzeDTO, poetry bool) ([]string, []string) {
+	cmd := strings.TrimSpace(m.StartCommand)
+	fields := strings.Fields(cmd)
+
+	var packages []string
+	need := func(bin, pkg string) {
+		if strings.Contains(cmd, bin) && !containsDependency(m.Dependencies, strings.SplitN(pkg, "[", 2)[0]) {
+			packages = append(packages, pkg)
+		}
+	}
+	need("gunicorn", "gunicorn")
+	need("uvicorn", "uvicorn[standard]")
+	need("daphne", "daphne")
+
+	entrypoint := fields
+	if strings.Contains(cmd, "$") {
+		entrypoint = []string{"/bin/sh", "-c", strings.ReplaceAll(cmd, `"`, `\"`)}
+	}
+	if poetry {
+		entrypoint = append([]string{"poetry", "run"}, entrypoint...)
+	}
+	return entrypoint, packages
+}
+
+func containsDependency(deps []string, name string) bool {
+	for _, d := range deps {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
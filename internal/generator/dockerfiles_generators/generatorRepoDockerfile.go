@@ -0,0 +1,31 @@
+package dockerfiles_generators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// useRepoDockerfile — Dockerfile репозитория, который анализатор привязал к модулю языка lang.
+// Он не копируется в gentmp: пайплайн и compose собирают его на месте со своим контекстом
+// (docker build -f <путь> <контекст>). Оставшийся от прошлых запусков gentmp/Dockerfile удаляется,
+// чтобы его не подхватили генераторы .dockerignore и docker-compose.dev.yml.
+func useRepoDockerfile(repoRoot string, analysis *dto.ProjectDTO, lang string) (string, bool) {
+	m := analysis.ModuleFor(lang)
+	if m == nil || m.Docker.DockerfilePath == "" {
+		return "", false
+	}
+	_ = os.Remove(dto.GeneratedDockerfile)
+	fmt.Printf("Using repository Dockerfile: %s (build context: %s)\n", m.Docker.Dockerfile(), m.Docker.Context())
+	if b, err := os.ReadFile(filepath.Join(repoRoot, m.Docker.DockerfilePath)); err == nil {
+		fmt.Println("----- Dockerfile -----")
+		fmt.Println(string(b))
+		fmt.Println("----- end -----")
+	}
+	if analysis.Options.Hardening.Enabled {
+		fmt.Println("Note: --hardened does not rewrite repository Dockerfiles; see the Dockerfile lint report for what to change")
+	}
+	return m.Docker.DockerfilePath, true
+}
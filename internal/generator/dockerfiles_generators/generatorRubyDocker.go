@@ -0,0 +1,98 @@
+package dockerfiles_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/util"
+)
+
+// GenerateRubyDockerfile генерирует мультистейдж Dockerfile для Ruby (Bundler).
+// Шаблон: templates/dockerfiles/ruby/slim/Dockerfile_ruby_bundler_multistage.tmpl
+// Свой Dockerfile модуля используется на месте, иначе результат — gentmp/Dockerfile.
+func GenerateRubyDockerfile(repoRoot string, analysis *dto.ProjectDTO) (string, error) {
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir gentmp: %w", err)
+	}
+	outPath := filepath.Join(tmpDir, "Dockerfile")
+
+	// 1) Dockerfile репозитория собирается на месте, без копии в gentmp
+	if path, ok := useRepoDockerfile(repoRoot, analysis, dto.LangRuby); ok {
+		return path, nil
+	}
+
+	// 2) Рендер из шаблона
+	tplPath := filepath.Join("templates", "dockerfiles", "ruby", "slim", "Dockerfile_ruby_bundler_multistage.tmpl")
+	raw, err := os.ReadFile(tplPath)
+	if err != nil {
+		return "", fmt.Errorf("read ruby dockerfile template: %w", err)
+	}
+	funcs := template.FuncMap{
+		"default": func(def string, val string) string {
+			if strings.TrimSpace(val) == "" {
+				return def
+			}
+			return val
+		},
+		"printf": fmt.Sprintf,
+	}
+	tpl, err := template.New("ruby-dockerfile").Funcs(funcs).Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse ruby dockerfile template: %w", err)
+	}
+
+	rubyVersion := "3.3"
+	appPort := ""
+	startCmd := ""
+	precompile := false
+	var module *dto.AnalyzeDTO
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			if m.Language != dto.LangRuby {
+				continue
+			}
+			module = m
+			if v := strings.TrimSpace(m.LanguageVersion); v != "" {
+				rubyVersion = v
+			}
+			appPort = m.Port()
+			startCmd = strings.TrimSpace(m.StartCommand)
+			precompile = m.Framework == "Rails" && dirExists(filepath.Join(repoRoot, "app", "assets"))
+			break
+		}
+	}
+
+	shellFreeBase(analysis, dto.LangRuby)
+	data := map[string]any{
+		"RubyVersion":      rubyVersion,
+		"BaseImageBuilder": fmt.Sprintf("ruby:%s-slim", rubyVersion),
+		"BaseImageRuntime": fmt.Sprintf("ruby:%s-slim", rubyVersion),
+		"AppWorkdir":       "/app",
+		"PrecompileAssets": precompile,
+		"Env":              map[string]string{},
+		"BuildArgs":        map[string]string{},
+		"StartCommand":     startCmd,
+		"ExposePort":       appPort,
+		"Hardening":        newHardening(analysis, module, appPort, runtimeFlavor{distro: distroDebian, probe: probeRuby}),
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render ruby dockerfile: %w", err)
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	util.PrintDockerfile(outPath, buf.Bytes())
+	return outPath, nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,75 @@
+package k8s_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// workloadConfig раскладывает инвентарь переменных окружения модуля: несекретные переменные
+// со значениями по умолчанию — в ConfigMap, секреты с плейсхолдером CHANGE_ME — в Secret.
+func workloadConfig(analysis *dto.ProjectDTO, m *dto.AnalyzeDTO) (config, secrets map[string]string) {
+	config = map[string]string{}
+	secrets = map[string]string{}
+	for _, v := range analysis.ModuleEnvVars(m) {
+		if v.Secret {
+			secrets[v.Name] = "CHANGE_ME"
+			continue
+		}
+		config[v.Name] = v.Default
+	}
+	return config, secrets
+}
+
+// renderSnippet рендерит шаблон из templates/snippets/k8s.
+func renderSnippet(name string, data any) ([]byte, error) {
+	raw, err := os.ReadFile(filepath.Join("templates", "snippets", "k8s", name))
+	if err != nil {
+		return nil, fmt.Errorf("read k8s template %s: %w", name, err)
+	}
+	tpl, err := template.New(name).Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse k8s template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render k8s template %s: %w", name, err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// sanitizeK8sName приводит имя к DNS-1123 label (строчные буквы, цифры, '-', до 63 символов).
+func sanitizeK8sName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	out := strings.Trim(b.String(), "-")
+	if len(out) > 63 {
+		out = strings.TrimRight(out[:63], "-")
+	}
+	if out == "" {
+		return "app"
+	}
+	return out
+}
+
+func getenvDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
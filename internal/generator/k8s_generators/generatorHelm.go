@@ -0,0 +1,115 @@
+package k8s_generators
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"gopkg.in/yaml.v3"
+)
+
+// helmChart — данные шаблонов templates/helm/chart: модель Workload плюс параметры чарта.
+// Шаблоны рендерятся с разделителями [[ ]], чтобы {{ }} Helm попадали в чарт как есть.
+type helmChart struct {
+	Workload
+	ChartName   string
+	Repository  string // образ без тега
+	Tag         string
+	StagingHost string
+}
+
+// GenerateHelmCharts рендерит по чарту на каждый деплоящийся модуль в gentmp/charts/<app>/:
+// Chart.yaml, values.yaml с образом, портом, env, пробами и ресурсами из анализа,
+// values-staging.yaml/values-production.yaml для стадий deploy_staging/deploy_production
+// и templates/ с хелперами.
+func GenerateHelmCharts(repoName string, analysis *dto.ProjectDTO) ([]string, error) {
+	workloads := BuildWorkloads(repoName, analysis)
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("no deployable modules for helm charts")
+	}
+	tplRoot := filepath.Join("templates", "helm", "chart")
+
+	var written []string
+	for _, w := range workloads {
+		chart := helmChart{Workload: w, ChartName: w.AppName}
+		chart.Repository, chart.Tag = splitImage(w.Image)
+		if w.Ingress != nil {
+			chart.StagingHost = EnvHost(w.Ingress.Host, "staging")
+		}
+		outDir := filepath.Join("gentmp", "charts", chart.ChartName)
+
+		err := filepath.WalkDir(tplRoot, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".tmpl") {
+				return err
+			}
+			rel, _ := filepath.Rel(tplRoot, path)
+			rel = strings.TrimSuffix(rel, ".tmpl")
+			if rel == "helmignore" {
+				rel = ".helmignore"
+			}
+			content, err := renderHelmFile(path, chart)
+			if err != nil {
+				return err
+			}
+			// values и Chart.yaml должны оставаться валидным YAML при любых данных анализа
+			if !strings.HasPrefix(filepath.ToSlash(rel), "templates/") && strings.HasSuffix(rel, ".yaml") {
+				var probe any
+				if err := yaml.Unmarshal(content, &probe); err != nil {
+					return fmt.Errorf("helm %s/%s: invalid yaml: %w", chart.ChartName, rel, err)
+				}
+			}
+			outPath := filepath.Join(outDir, rel)
+			if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+				return fmt.Errorf("mkdir %s: %w", filepath.Dir(outPath), err)
+			}
+			if err := os.WriteFile(outPath, content, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", outPath, err)
+			}
+			written = append(written, outPath)
+			return nil
+		})
+		if err != nil {
+			return written, err
+		}
+		for _, name := range []string{"Chart.yaml", "values.yaml", "values-staging.yaml", "values-production.yaml"} {
+			content, _ := os.ReadFile(filepath.Join(outDir, name))
+			fmt.Println("----- charts/" + chart.ChartName + "/" + name + " -----")
+			fmt.Print(string(content))
+			fmt.Println("----- end -----")
+		}
+		fmt.Println("Saved chart to:", outDir)
+	}
+	return written, nil
+}
+
+func renderHelmFile(path string, data helmChart) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read helm template %s: %w", path, err)
+	}
+	tpl, err := template.New(filepath.Base(path)).Delims("[[", "]]").Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse helm template %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render helm template %s: %w", path, err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// splitImage: "registry/app:1.0" -> "registry/app", "1.0"; порт реестра тегом не считается.
+func splitImage(image string) (repository, tag string) {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
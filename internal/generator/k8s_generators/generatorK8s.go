@@ -0,0 +1,50 @@
+package k8s_generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+)
+
+// Mode — в каком виде выдавать k8s-конфигурацию.
+type Mode string
+
+const (
+	ModeManifests Mode = "manifests" // gentmp/k8s: готовые манифесты
+	ModeHelm      Mode = "helm"      // плюс gentmp/charts/<app>
+	ModeKustomize Mode = "kustomize" // плюс gentmp/deploy/{base,overlays}
+)
+
+// Modes — поддерживаемые режимы в порядке для справки CLI.
+var Modes = []Mode{ModeManifests, ModeHelm, ModeKustomize}
+
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "manifests", "k8s":
+		return ModeManifests, nil
+	case "helm":
+		return ModeHelm, nil
+	case "kustomize", "kustomization":
+		return ModeKustomize, nil
+	}
+	return "", fmt.Errorf("unknown k8s mode %q (want manifests, helm or kustomize)", s)
+}
+
+// Generate выдаёт k8s-конфигурацию в режиме mode. Манифесты проверяются по схемам Kubernetes
+// и пишутся всегда, остальные режимы строятся поверх той же модели Workload.
+// repoRoot нужен Kustomize, чтобы дополнить существующий kustomization.yaml.
+func Generate(mode Mode, repoName, repoRoot string, analysis *dto.ProjectDTO) ([]string, error) {
+	written, err := GenerateManifests(repoName, analysis)
+	if err != nil || mode == ModeManifests {
+		return written, err
+	}
+	var more []string
+	switch mode {
+	case ModeHelm:
+		more, err = GenerateHelmCharts(repoName, analysis)
+	case ModeKustomize:
+		more, err = GenerateKustomize(repoName, repoRoot, analysis)
+	}
+	return append(written, more...), err
+}
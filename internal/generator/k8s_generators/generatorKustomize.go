@@ -0,0 +1,360 @@
+package k8s_generators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/kubeschema"
+	"gopkg.in/yaml.v3"
+)
+
+type kustomizeOverlay struct {
+	Env       string
+	Resources []string
+	Apps      []kustomizeApp
+}
+
+type kustomizeApp struct {
+	AppName    string
+	Repository string
+	Tag        string
+	Replicas   int
+	ConfigFile string
+}
+
+// kustomizeEnvs — окружения overlays: в staging одна реплика без HPA, в production — HPA.
+var kustomizeEnvs = []struct {
+	name string
+	hpa  bool
+}{
+	{"staging", false},
+	{"production", true},
+}
+
+// GenerateKustomize раскладывает модель Workload в Kustomize: gentmp/deploy/base с Deployment
+// и Service и gentmp/deploy/overlays/{staging,production} с тегом образа, числом реплик,
+// ConfigMap окружения из configMapGenerator и Ingress/HPA окружения. Secret <app>-secret в
+// overlays не входит: deploy-джоба создаёт его из CI/CD-переменных окружения.
+// Если в репозитории уже есть kustomization.yaml, overlays собираются поверх него (см. extendKustomization).
+func GenerateKustomize(repoName, repoRoot string, analysis *dto.ProjectDTO) ([]string, error) {
+	workloads := BuildWorkloads(repoName, analysis)
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("no deployable modules for kustomize")
+	}
+	schema, err := kubeschema.Load(kubeschema.DefaultVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(analysis.KustomizeFiles) > 0 {
+		return extendKustomization(schema, repoRoot, analysis.KustomizeFiles, workloads)
+	}
+
+	// 1) base: манифесты без окружения; ConfigMap создают overlays
+	baseDir := filepath.Join("gentmp", "deploy", "base")
+	var written, baseResources []string
+	for _, w := range workloads {
+		// число реплик задают overlays: staging — replicas, production — HPA
+		w.Replicas = 0
+		files, err := writeKustomizeBase(schema, w, filepath.Join(baseDir, w.Dir), false)
+		written = append(written, files...)
+		if err != nil {
+			return written, err
+		}
+		if w.Dir != "" {
+			baseResources = append(baseResources, w.Dir)
+			continue
+		}
+		for _, f := range files {
+			if filepath.Base(f) != "kustomization.yaml" {
+				baseResources = append(baseResources, filepath.Base(f))
+			}
+		}
+	}
+	path := filepath.Join(baseDir, "kustomization.yaml")
+	if err := writeKustomization(path, "kustomization.yaml.tmpl", map[string]any{"Resources": baseResources}); err != nil {
+		return written, err
+	}
+	written = append(written, path)
+
+	// 2) overlays
+	for _, env := range kustomizeEnvs {
+		overlayDir := filepath.Join("gentmp", "deploy", "overlays", env.name)
+		if err := os.MkdirAll(overlayDir, 0o755); err != nil {
+			return written, fmt.Errorf("mkdir %s: %w", overlayDir, err)
+		}
+		overlay := kustomizeOverlay{Env: env.name, Resources: []string{"../../base"}}
+		for _, w := range workloads {
+			app := kustomizeApp{AppName: w.AppName}
+			app.Repository, app.Tag = splitImage(w.Image)
+			if !env.hpa {
+				// с HPA replicas в overlay сбрасывал бы число реплик при каждом apply
+				app.Replicas = 1
+			}
+			if len(w.Config) > 0 {
+				app.ConfigFile = overlayFile(w, "config.env")
+				path := filepath.Join(overlayDir, app.ConfigFile)
+				if err := writeEnvFile(path, env.name, w); err != nil {
+					return written, err
+				}
+				written = append(written, path)
+			}
+			overlay.Apps = append(overlay.Apps, app)
+
+			envW := w
+			if w.Ingress != nil {
+				envW.Ingress = &Ingress{Host: EnvHost(w.Ingress.Host, env.name), ClassName: w.Ingress.ClassName}
+				name := overlayFile(w, "ingress.yaml")
+				if err := writeManifest(schema, "ingress.yaml.tmpl", envW, filepath.Join(overlayDir, name)); err != nil {
+					return written, err
+				}
+				overlay.Resources = append(overlay.Resources, name)
+				written = append(written, filepath.Join(overlayDir, name))
+			}
+			if env.hpa {
+				name := overlayFile(w, "hpa.yaml")
+				if err := writeManifest(schema, "hpa.yaml.tmpl", envW, filepath.Join(overlayDir, name)); err != nil {
+					return written, err
+				}
+				overlay.Resources = append(overlay.Resources, name)
+				written = append(written, filepath.Join(overlayDir, name))
+			}
+		}
+		path := filepath.Join(overlayDir, "kustomization.yaml")
+		if err := writeKustomization(path, "kustomization_overlay.yaml.tmpl", overlay); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// writeKustomizeBase пишет Deployment, Service (и ConfigMap, если его не генерируют overlays)
+// модуля и, если модуль в своём подкаталоге, его kustomization.yaml.
+func writeKustomizeBase(schema *kubeschema.Schema, w Workload, dir string, withConfig bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	var written, resources []string
+	for _, item := range []struct {
+		tpl, out string
+		skip     bool
+	}{
+		{"deployment.yaml.tmpl", "deployment.yaml", false},
+		{"service.yaml.tmpl", "service.yaml", w.Port == 0},
+		{"configmap.yaml.tmpl", "configmap.yaml", !withConfig || len(w.Config) == 0},
+	} {
+		if item.skip {
+			continue
+		}
+		path := filepath.Join(dir, item.out)
+		if err := writeManifest(schema, item.tpl, w, path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+		resources = append(resources, item.out)
+	}
+	if w.Dir != "" {
+		path := filepath.Join(dir, "kustomization.yaml")
+		if err := writeKustomization(path, "kustomization.yaml.tmpl", map[string]any{"Resources": resources}); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// extendKustomization дополняет kustomize-конфигурацию репозитория модулями, которых в ней нет.
+// Манифесты модуля ложатся в gentmp/deploy/<app>/, а gentmp/deploy/overlays/<env>/kustomization.yaml
+// собирает overlay репозитория для env (или его базу) вместе с ними: kubectl apply -k применяет
+// всё сразу, файлы репозитория не меняются. Модули, чей Deployment уже перечислен в resources базы,
+// пропускаются.
+func extendKustomization(schema *kubeschema.Schema, repoRoot string, files []string, workloads []Workload) ([]string, error) {
+	existing := existingKustomization(files)
+	srcPath := filepath.Join(repoRoot, filepath.FromSlash(existing))
+	raw, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", existing, err)
+	}
+	var base struct {
+		Resources []string `yaml:"resources"`
+	}
+	if err := yaml.Unmarshal(raw, &base); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", existing, err)
+	}
+
+	deployDir := filepath.Join("gentmp", "deploy")
+	var written, added []string
+	for _, w := range workloads {
+		if declaresDeployment(filepath.Dir(srcPath), base.Resources, w.AppName) {
+			fmt.Printf("Kustomize: %s already declares %s, skipping\n", existing, w.AppName)
+			continue
+		}
+		w.Dir = w.AppName
+		// overlays репозитория о ConfigMap модуля не знают — он идёт вместе с Deployment
+		files, err := writeKustomizeBase(schema, w, filepath.Join(deployDir, w.AppName), true)
+		written = append(written, files...)
+		if err != nil {
+			return written, err
+		}
+		added = append(added, "../../"+w.AppName)
+	}
+
+	for _, env := range kustomizeEnvs {
+		overlayDir := filepath.Join(deployDir, "overlays", env.name)
+		if err := os.MkdirAll(overlayDir, 0o755); err != nil {
+			return written, fmt.Errorf("mkdir %s: %w", overlayDir, err)
+		}
+		// gentmp лежит в корне репозитория, overlay — на четыре уровня ниже
+		source := repoKustomizeDir(files, env.name)
+		data := map[string]any{
+			"Env":       env.name,
+			"Source":    source,
+			"Resources": append([]string{path.Join("../../../..", source)}, added...),
+		}
+		outPath := filepath.Join(overlayDir, "kustomization.yaml")
+		if err := writeKustomization(outPath, "kustomization_extend.yaml.tmpl", data); err != nil {
+			return written, err
+		}
+		written = append(written, outPath)
+	}
+	return written, nil
+}
+
+// repoKustomizeDir — каталог kustomize-конфигурации репозитория для окружения env: overlay
+// (…/<env>/kustomization.yaml), иначе база, которую выбирает existingKustomization.
+func repoKustomizeDir(files []string, env string) string {
+	for _, f := range files {
+		if dir := path.Dir(f); path.Base(dir) == env {
+			return dir
+		}
+	}
+	return path.Dir(existingKustomization(files))
+}
+
+// existingKustomization выбирает kustomization.yaml, в который добавлять сервисы: база
+// (каталог base), иначе ближайший к корню репозитория.
+func existingKustomization(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if di, dj := strings.Count(sorted[i], "/"), strings.Count(sorted[j], "/"); di != dj {
+			return di < dj
+		}
+		return sorted[i] < sorted[j]
+	})
+	for _, f := range sorted {
+		if filepath.Base(filepath.Dir(filepath.FromSlash(f))) == "base" {
+			return f
+		}
+	}
+	for _, f := range sorted {
+		if !strings.Contains("/"+f, "/overlays/") {
+			return f
+		}
+	}
+	return sorted[0]
+}
+
+// declaresDeployment сообщает, перечислен ли в resources Deployment с именем app:
+// каталог <app> или YAML-файл с таким Deployment.
+func declaresDeployment(dir string, resources []string, app string) bool {
+	for _, r := range resources {
+		if filepath.Base(strings.TrimSuffix(r, "/")) == app {
+			return true
+		}
+		if !strings.HasSuffix(r, ".yaml") && !strings.HasSuffix(r, ".yml") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r)))
+		if err != nil {
+			continue
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		for {
+			var obj struct {
+				Kind     string `yaml:"kind"`
+				Metadata struct {
+					Name string `yaml:"name"`
+				} `yaml:"metadata"`
+			}
+			if err := dec.Decode(&obj); err != nil {
+				if !errors.Is(err, io.EOF) {
+					fmt.Println("Kustomize: skip", r+":", err)
+				}
+				break
+			}
+			if obj.Kind == "Deployment" && obj.Metadata.Name == app {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeKustomization(path, tpl string, data any) error {
+	content, err := renderSnippet(tpl, data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	rel, _ := filepath.Rel("gentmp", path)
+	fmt.Println("----- " + filepath.ToSlash(rel) + " -----")
+	fmt.Print(string(content))
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", path)
+	return nil
+}
+
+// writeEnvFile пишет KEY=value для configMapGenerator: значения по умолчанию из кода,
+// которые правятся под окружение.
+func writeEnvFile(path, env string, w Workload) error {
+	keys := make([]string, 0, len(w.Config))
+	for k := range w.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: конфигурация окружения %s (ConfigMap %s-config)\n", w.AppName, env, w.AppName)
+	for _, k := range keys {
+		b.WriteString(k + "=" + w.Config[k] + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// overlayFile: в монорепозитории ресурсы overlays получают префикс модуля.
+func overlayFile(w Workload, name string) string {
+	if w.Dir == "" {
+		return name
+	}
+	return w.Dir + "-" + name
+}
+
+// EnvHost: хост production — как есть, остальных окружений — <app>.<env>.<домен>.
+func EnvHost(host, env string) string {
+	if env == "production" {
+		return host
+	}
+	app, domain, _ := strings.Cut(host, ".")
+	return app + "." + env + "." + domain
+}
+
+// KustomizeOverlay — каталог, который применяется kubectl apply -k для окружения env:
+// сгенерированный overlay, в том числе поверх kustomize-конфигурации репозитория.
+func KustomizeOverlay(env string) string {
+	return "gentmp/deploy/overlays/" + env
+}
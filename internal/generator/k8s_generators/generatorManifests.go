@@ -0,0 +1,254 @@
+package k8s_generators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
+	"github.com/Dancoi/gogen-self-deploy/internal/kubeschema"
+)
+
+// Workload — k8s-модель одного деплоящегося модуля. Из неё рендерятся все шаблоны
+// templates/snippets/k8s; Helm и Kustomize строятся поверх этой же модели.
+type Workload struct {
+	AppName   string // DNS-1123 имя Deployment/Service/ConfigMap/Secret
+	Module    string // имя модуля из анализа
+	Dir       string // подкаталог вывода относительно gentmp/k8s ("" — единственный модуль)
+	Namespace string // "" — namespace текущего контекста kubectl
+	Image     string
+	Port      int // 0 — модуль не слушает порт: без Service, Ingress и проб
+	Replicas  int
+	Config    map[string]string // ConfigMap <app>-config
+	Secrets   map[string]string // Secret <app>-secret
+	Resources Resources
+	Probe     *Probe
+	HPA       Autoscaling
+	Ingress   *Ingress
+	Security  *Security // nil — без усиленного профиля
+}
+
+type Resources struct {
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// Probe — readiness/liveness; пустой путь — проверка tcpSocket на порт приложения.
+type Probe struct {
+	ReadinessPath       string
+	LivenessPath        string
+	InitialDelaySeconds int
+}
+
+// Security — усиленный профиль (--hardened): под запускается от UID образа без повышения привилегий,
+// корневая ФС только для чтения, каталоги, куда пишет рантайм, — emptyDir.
+type Security struct {
+	RunAsUser int
+	Volumes   []Volume
+}
+
+// Volume — emptyDir, смонтированный в Path.
+type Volume struct {
+	Name string
+	Path string
+}
+
+type Autoscaling struct {
+	MinReplicas    int
+	MaxReplicas    int
+	CPUUtilization int
+}
+
+type Ingress struct {
+	Host      string
+	ClassName string
+}
+
+// resourcesByLang — стартовые requests/limits по рантайму; JVM нужен запас памяти под heap и metaspace.
+var resourcesByLang = map[string]Resources{
+	dto.LangJava:   {CPURequest: "250m", CPULimit: "1", MemoryRequest: "512Mi", MemoryLimit: "1Gi"},
+	dto.LangNode:   {CPURequest: "100m", CPULimit: "500m", MemoryRequest: "128Mi", MemoryLimit: "512Mi"},
+	dto.LangPython: {CPURequest: "100m", CPULimit: "500m", MemoryRequest: "256Mi", MemoryLimit: "512Mi"},
+	dto.LangGo:     {CPURequest: "50m", CPULimit: "500m", MemoryRequest: "64Mi", MemoryLimit: "256Mi"},
+	dto.LangPHP:    {CPURequest: "100m", CPULimit: "500m", MemoryRequest: "128Mi", MemoryLimit: "512Mi"},
+	dto.LangRuby:   {CPURequest: "100m", CPULimit: "500m", MemoryRequest: "256Mi", MemoryLimit: "512Mi"},
+}
+
+// staticSiteResources — nginx со статикой.
+var staticSiteResources = Resources{CPURequest: "10m", CPULimit: "200m", MemoryRequest: "32Mi", MemoryLimit: "128Mi"}
+
+// staticSitePort — порт nginx в Dockerfile_node_spa.tmpl по умолчанию.
+const staticSitePort = 8080
+
+// BuildWorkloads строит k8s-модель для каждого деплоящегося модуля. Имя единственного модуля —
+// имя репозитория (как у сервиса в docker-compose), в монорепозитории — имя образа модуля.
+// Реестр, тег, namespace и домен Ingress берутся из REGISTRY_PROJECT, IMAGE_TAG, K8S_NAMESPACE, INGRESS_DOMAIN.
+func BuildWorkloads(repoName string, analysis *dto.ProjectDTO) []Workload {
+	if analysis == nil {
+		return nil
+	}
+	var modules []*dto.AnalyzeDTO
+	for _, m := range analysis.Modules {
+		if m.Language != "" && m.Language != "unknown" {
+			modules = append(modules, m)
+		}
+	}
+
+	registry := strings.TrimSuffix(getenvDefault("REGISTRY_PROJECT", "registry.example.com/"+sanitizeK8sName(repoName)), "/")
+	tag := getenvDefault("IMAGE_TAG", "latest")
+	domain := getenvDefault("INGRESS_DOMAIN", "example.com")
+
+	workloads := make([]Workload, 0, len(modules))
+	for _, m := range modules {
+		w := Workload{
+			AppName:   sanitizeK8sName(repoName),
+			Module:    m.Name,
+			Namespace: os.Getenv("K8S_NAMESPACE"),
+			Image:     registry + ":" + tag,
+			Port:      m.AppPort,
+			Replicas:  2,
+			HPA:       Autoscaling{MinReplicas: 2, MaxReplicas: 5, CPUUtilization: 70},
+		}
+		if len(modules) > 1 {
+			w.AppName = sanitizeK8sName(m.Docker.ImageName)
+			w.Dir = w.AppName
+			w.Image = registry + "/" + m.Docker.ImageName + ":" + tag
+		}
+		w.Config, w.Secrets = workloadConfig(analysis, m)
+
+		w.Resources = resourcesByLang[m.CanonicalLanguage()]
+		if m.StaticSite() {
+			w.Resources = staticSiteResources
+			if w.Port == 0 {
+				w.Port = staticSitePort
+			}
+		}
+		if w.Resources.CPURequest == "" {
+			w.Resources = resourcesByLang[dto.LangNode]
+		}
+
+		if analysis.Options.Hardening.Enabled {
+			w.Security = workloadSecurity(m)
+		}
+
+		if w.Port > 0 {
+			w.Probe = workloadProbe(m)
+			w.Ingress = &Ingress{
+				Host:      w.AppName + "." + domain,
+				ClassName: getenvDefault("INGRESS_CLASS", "nginx"),
+			}
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads
+}
+
+// workloadProbe: Spring Boot Actuator отдаёт отдельные группы liveness/readiness,
+// статика отвечает на "/", найденный анализатором эндпоинт (/healthz, /health) проверяется по HTTP,
+// для остальных достаточно открытого порта.
+func workloadProbe(m *dto.AnalyzeDTO) *Probe {
+	delay := 5
+	if m.CanonicalLanguage() == dto.LangJava {
+		delay = 30
+	}
+	switch {
+	case m.StaticSite():
+		return &Probe{ReadinessPath: "/", LivenessPath: "/", InitialDelaySeconds: 2}
+	case hasDependency(m, "spring-boot-starter-actuator"):
+		return &Probe{ReadinessPath: "/actuator/health/readiness", LivenessPath: "/actuator/health/liveness", InitialDelaySeconds: delay}
+	case m.HealthPath != "":
+		return &Probe{ReadinessPath: m.HealthPath, LivenessPath: m.HealthPath, InitialDelaySeconds: delay}
+	}
+	return &Probe{InitialDelaySeconds: delay}
+}
+
+// workloadSecurity — тот же UID, что в образе (USER), и emptyDir для каталогов, куда пишет рантайм.
+func workloadSecurity(m *dto.AnalyzeDTO) *Security {
+	s := &Security{RunAsUser: dockerfiles_generators.HardenedUID}
+	for _, p := range dockerfiles_generators.WritablePaths(m) {
+		s.Volumes = append(s.Volumes, Volume{Name: sanitizeK8sName(p), Path: p})
+	}
+	return s
+}
+
+func hasDependency(m *dto.AnalyzeDTO, artifact string) bool {
+	for _, d := range m.Dependencies {
+		if d == artifact || strings.HasSuffix(d, ":"+artifact) || strings.Contains(d, ":"+artifact+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestFiles — шаблон и файл вывода; порт нужен Service и Ingress.
+var manifestFiles = []struct {
+	tpl, out  string
+	needsPort bool
+}{
+	{"configmap.yaml.tmpl", "configmap.yaml", false},
+	{"secret.yaml.tmpl", "secret.yaml", false},
+	{"deployment.yaml.tmpl", "deployment.yaml", false},
+	{"service.yaml.tmpl", "service.yaml", true},
+	{"hpa.yaml.tmpl", "hpa.yaml", false},
+	{"ingress.yaml.tmpl", "ingress.yaml", true},
+}
+
+// GenerateManifests рендерит Deployment, Service, ConfigMap, Secret, HPA и Ingress для каждого
+// деплоящегося модуля в gentmp/k8s (в монорепозитории — gentmp/k8s/<модуль>/) и проверяет их
+// офлайн по схемам Kubernetes из templates/schemas/k8s.
+func GenerateManifests(repoName string, analysis *dto.ProjectDTO) ([]string, error) {
+	workloads := BuildWorkloads(repoName, analysis)
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("no deployable modules for k8s manifests")
+	}
+	schema, err := kubeschema.Load(kubeschema.DefaultVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, w := range workloads {
+		// рядом всегда лежит hpa.yaml: spec.replicas сбрасывал бы число реплик HPA при каждом apply
+		w.Replicas = 0
+		outDir := filepath.Join("gentmp", "k8s", w.Dir)
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return written, fmt.Errorf("mkdir %s: %w", outDir, err)
+		}
+		for _, item := range manifestFiles {
+			if item.needsPort && w.Port == 0 {
+				continue
+			}
+			outPath := filepath.Join(outDir, item.out)
+			if err := writeManifest(schema, item.tpl, w, outPath); err != nil {
+				return written, err
+			}
+			written = append(written, outPath)
+		}
+	}
+	return written, nil
+}
+
+// writeManifest рендерит шаблон для w, проверяет результат по схемам Kubernetes, сохраняет и печатает его.
+func writeManifest(schema *kubeschema.Schema, tpl string, w Workload, outPath string) error {
+	content, err := renderSnippet(tpl, w)
+	if err != nil {
+		return err
+	}
+	rel, _ := filepath.Rel("gentmp", outPath)
+	rel = filepath.ToSlash(rel)
+	if err := schema.Validate(content); err != nil {
+		return fmt.Errorf("validate %s against Kubernetes %s: %w", rel, schema.Version, err)
+	}
+	if err := os.WriteFile(outPath, content, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", rel, err)
+	}
+	fmt.Println("----- " + rel + " -----")
+	fmt.Print(string(content))
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return nil
+}
@@ -0,0 +1,364 @@
+package pipelines_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/k8s_generators"
+	"gopkg.in/yaml.v3"
+)
+
+// DeployStrategy — чем deploy_staging/deploy_production выкатывают собранный образ.
+type DeployStrategy string
+
+const (
+	DeployKubectl DeployStrategy = "kubectl" // kubectl apply манифестов или overlay Kustomize
+	DeployHelm    DeployStrategy = "helm"    // helm upgrade --install чартов gentmp/charts
+	DeployCompose DeployStrategy = "compose" // docker compose на VM по SSH
+	DeployArgoCD  DeployStrategy = "argocd"  // коммит тега в GitOps-репозиторий, выкатывает Argo CD
+)
+
+// DeployStrategies — поддерживаемые стратегии в порядке для справки CLI.
+var DeployStrategies = []DeployStrategy{DeployKubectl, DeployHelm, DeployCompose, DeployArgoCD}
+
+// ParseDeployStrategy разбирает стратегию; пустая — по формату k8s: helm для чартов, иначе kubectl.
+func ParseDeployStrategy(s string, mode k8s_generators.Mode) (DeployStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		if mode == k8s_generators.ModeHelm {
+			return DeployHelm, nil
+		}
+		return DeployKubectl, nil
+	case "kubectl", "k8s":
+		return DeployKubectl, nil
+	case "helm":
+		return DeployHelm, nil
+	case "compose", "docker-compose", "ssh":
+		return DeployCompose, nil
+	case "argocd", "argo", "gitops":
+		return DeployArgoCD, nil
+	}
+	return "", fmt.Errorf("unknown deploy strategy %q (want kubectl, helm, compose or argocd)", s)
+}
+
+// K8sMode — формат k8s-конфигурации, который нужен стратегии: helm выкатывает чарты,
+// Argo CD переопределяет тег только у Helm/Kustomize-приложений.
+func (s DeployStrategy) K8sMode(mode k8s_generators.Mode) k8s_generators.Mode {
+	switch {
+	case s == DeployHelm:
+		return k8s_generators.ModeHelm
+	case s == DeployArgoCD && mode == k8s_generators.ModeManifests:
+		return k8s_generators.ModeKustomize
+	}
+	return mode
+}
+
+// DeployOptions — параметры джоб деплоя из флагов CLI.
+type DeployOptions struct {
+	Strategy   DeployStrategy
+	K8sMode    k8s_generators.Mode // формат k8s-конфигурации, уже согласованный со стратегией
+	ReviewApps bool                // review app на каждый merge request
+	Module     string              // модуль, образ которого docker-джоба публикует как $CI_REGISTRY_IMAGE; "" — первый
+}
+
+type deployApp struct {
+	AppName    string
+	Image      string // образ docker-джобы без тега
+	Repository string // образ в манифестах/чарте без тега
+	Manifests  string
+	Chart      string
+	ArgoApp    string
+	Host       string // хост Ingress в выкатываемых манифестах
+	ReviewHost string
+	Secret     string   // Secret, который джоба создаёт из CI/CD-переменных
+	Secrets    []string // CI/CD-переменные окружения со значениями секретов
+}
+
+type deployTplData struct {
+	Strategy       DeployStrategy
+	K8sMode        k8s_generators.Mode
+	Env            string
+	Profile        string
+	Review         bool
+	Stop           bool
+	URL            string
+	Namespace      string
+	Overlay        string
+	GitOpsPath     string
+	ComposeFile    string
+	ComposeService string
+	ComposeProject string
+	AutoStopIn     string
+	Apps           []deployApp
+	Teardown       *deployTplData // данные stop_review
+}
+
+// deployJobNames — джобы-заглушки деплоя в шаблонах пайплайнов, которые заменяются настоящими.
+var deployJobNames = regexp.MustCompile(`^(deploy|deploy_staging|deploy_production|deploy_review|stop_review):\s*$`)
+
+// GenerateDeployJobs заменяет заглушки деплоя в gentmp/.gitlab-ci.yml джобами из
+// templates/gitlab/includes/common/deploy_{staging,production}.yml.tmpl: staging выкатывается
+// автоматически, production — ручной шлюз; обе джобы привязаны к environment и resource_group.
+// С ReviewApps добавляются deploy_review/stop_review (deploy_review.yml.tmpl) на стадии review.
+func GenerateDeployJobs(opts DeployOptions, repoName string, analysis *dto.ProjectDTO) error {
+	outPath := filepath.Join("gentmp", ".gitlab-ci.yml")
+	raw, err := os.ReadFile(outPath)
+	if err != nil {
+		return fmt.Errorf("read pipeline: %w", err)
+	}
+	workloads := k8s_generators.BuildWorkloads(repoName, analysis)
+	if len(workloads) == 0 {
+		return fmt.Errorf("no deployable modules for deploy jobs")
+	}
+
+	envs := []string{"staging", "production"}
+	var stages []string
+	if opts.ReviewApps {
+		envs = append([]string{"review"}, envs...)
+		stages = append(stages, "review")
+	}
+	var jobs bytes.Buffer
+	for _, env := range envs {
+		data := deployData(opts, env, repoName, string(raw), analysis, workloads)
+		if err := renderDeployJob(&jobs, env, data); err != nil {
+			return err
+		}
+	}
+
+	pipeline := ensureStages(stripDeployJobs(string(raw)), stages...)
+	pipeline = strings.TrimRight(pipeline, "\n") + "\n\n" + jobs.String()
+	var probe any
+	if err := yaml.Unmarshal([]byte(pipeline), &probe); err != nil {
+		return fmt.Errorf("pipeline with deploy jobs is not valid yaml: %w", err)
+	}
+	if err := os.WriteFile(outPath, []byte(pipeline), 0o644); err != nil {
+		return fmt.Errorf("write .gitlab-ci.yml: %w", err)
+	}
+	fmt.Println("----- .gitlab-ci.yml (deploy: " + string(opts.Strategy) + ") -----")
+	fmt.Print(jobs.String())
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return nil
+}
+
+// deployData собирает данные шаблонов деплоя. В монорепозитории модуль выкатывается из образа
+// $CI_REGISTRY_IMAGE/<модуль>, если его публикует docker-джоба пайплайна; иначе выкатывается только
+// модуль, который собирает пайплайн (DeployOptions.Module), из $CI_REGISTRY_IMAGE.
+// Review app выкатывает конфигурацию staging в namespace/compose-проект из $CI_ENVIRONMENT_SLUG;
+// при стратегии argocd — напрямую через helm/kubectl: временным окружениям GitOps не нужен.
+func deployData(opts DeployOptions, env, repoName, pipeline string, analysis *dto.ProjectDTO, workloads []k8s_generators.Workload) deployTplData {
+	workloads = pipelineWorkloadFirst(workloads, opts.Module)
+	app := sanitizeName(repoName)
+	review := env == "review"
+	profile := env
+	if review {
+		profile = "staging"
+	}
+	data := deployTplData{
+		Strategy:       opts.Strategy,
+		K8sMode:        opts.K8sMode,
+		Env:            env,
+		Profile:        profile,
+		Review:         review,
+		Namespace:      app + "-" + env,
+		Overlay:        k8s_generators.KustomizeOverlay(profile),
+		GitOpsPath:     "deploy/overlays/" + env,
+		ComposeFile:    "gentmp/docker-compose.yml",
+		ComposeService: app,
+		ComposeProject: app,
+		AutoStopIn:     getenvDefault("REVIEW_AUTO_STOP_IN", "3 days"),
+	}
+	if data.K8sMode == k8s_generators.ModeHelm {
+		data.GitOpsPath = "charts"
+	}
+	if review {
+		data.Namespace = app + "-$CI_ENVIRONMENT_SLUG"
+		data.ComposeProject = "$CI_ENVIRONMENT_SLUG"
+		if data.Strategy == DeployArgoCD {
+			data.Strategy = DeployKubectl
+			if data.K8sMode == k8s_generators.ModeHelm {
+				data.Strategy = DeployHelm
+			}
+		}
+	}
+	for i, w := range workloads {
+		if w.Namespace != "" && !review {
+			data.Namespace = w.Namespace
+		}
+		moduleImage := w.Dir != "" && strings.Contains(pipeline, "}/"+w.Dir+"\"")
+		if w.Dir != "" && !moduleImage && i > 0 {
+			if env == "staging" {
+				fmt.Printf("Deploy: pipeline does not publish an image for %s, skipping\n", w.AppName)
+			}
+			continue
+		}
+		a := deployApp{
+			AppName:   w.AppName,
+			Image:     "$CI_REGISTRY_IMAGE",
+			Manifests: "gentmp/k8s",
+			Chart:     "gentmp/charts/" + w.AppName,
+			ArgoApp:   w.AppName + "-" + env,
+		}
+		if moduleImage {
+			a.Image += "/" + w.Dir
+		}
+		if len(w.Secrets) > 0 {
+			a.Secret = w.AppName + "-secret"
+			for k := range w.Secrets {
+				a.Secrets = append(a.Secrets, k)
+			}
+			sort.Strings(a.Secrets)
+		}
+		if w.Dir != "" {
+			a.Manifests += "/" + w.Dir
+		}
+		a.Repository = w.Image
+		if i := strings.LastIndex(w.Image, ":"); i > strings.LastIndex(w.Image, "/") {
+			a.Repository = w.Image[:i]
+		}
+		if w.Ingress != nil {
+			a.Host = w.Ingress.Host
+			if data.K8sMode == k8s_generators.ModeKustomize {
+				a.Host = k8s_generators.EnvHost(w.Ingress.Host, profile)
+			}
+			// <slug>.<домен>, в монорепозитории — <модуль>-<slug>.<домен>
+			_, domain, _ := strings.Cut(w.Ingress.Host, ".")
+			a.ReviewHost = "$CI_ENVIRONMENT_SLUG." + domain
+			if len(workloads) > 1 {
+				a.ReviewHost = w.AppName + "-" + a.ReviewHost
+			}
+			// environment:url — первый модуль с Ingress
+			if data.URL == "" {
+				data.URL = "https://" + k8s_generators.EnvHost(w.Ingress.Host, env)
+				if review {
+					data.URL = "https://" + a.ReviewHost
+				}
+			}
+		}
+		data.Apps = append(data.Apps, a)
+	}
+	if data.K8sMode == k8s_generators.ModeKustomize {
+		data.Apps[0].ArgoApp = app + "-" + env
+	}
+
+	// compose: порт приложения на VM; порт review app вычисляется в джобе и приходит через dotenv-отчёт
+	if data.Strategy == DeployCompose {
+		data.URL = ""
+		if workloads[0].Port > 0 {
+			data.URL = fmt.Sprintf("http://$DEPLOY_HOST:%d", workloads[0].Port)
+		}
+		if review {
+			data.URL = "$REVIEW_URL"
+		}
+		data.Apps = data.Apps[:1]
+	}
+	if review {
+		stop := data
+		stop.Stop = true
+		data.Teardown = &stop
+	}
+	return data
+}
+
+// pipelineWorkloadFirst ставит первым workload модуля, который собирает пайплайн: ему достаётся
+// $CI_REGISTRY_IMAGE, URL окружения и compose-деплой. Порядок остальных сохраняется.
+func pipelineWorkloadFirst(workloads []k8s_generators.Workload, module string) []k8s_generators.Workload {
+	for i, w := range workloads {
+		if i > 0 && w.Module == module {
+			out := append([]k8s_generators.Workload{w}, workloads[:i]...)
+			return append(out, workloads[i+1:]...)
+		}
+	}
+	return workloads
+}
+
+func renderDeployJob(buf *bytes.Buffer, env string, data deployTplData) error {
+	dir := filepath.Join("templates", "gitlab", "includes", "common")
+	name := "deploy_" + env + ".yml.tmpl"
+	tpl, err := template.New(name).Option("missingkey=zero").ParseFiles(filepath.Join(dir, name), filepath.Join(dir, "deploy_job.yml.tmpl"))
+	if err != nil {
+		return fmt.Errorf("parse deploy template: %w", err)
+	}
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	if err := tpl.ExecuteTemplate(buf, name, data); err != nil {
+		return fmt.Errorf("render %s: %w", name, err)
+	}
+	return nil
+}
+
+// stripDeployJobs вырезает верхнеуровневые джобы деплоя вместе с их телом.
+func stripDeployJobs(pipeline string) string {
+	var out []string
+	skip := false
+	for _, line := range strings.Split(pipeline, "\n") {
+		topLevel := line != "" && line[0] != ' ' && line[0] != '\t'
+		if topLevel {
+			skip = deployJobNames.MatchString(line)
+		}
+		if !skip {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// ensureStages приводит список stages к deploy_staging/deploy_production вместо deploy,
+// добавляет стадии, на которые ссылаются джобы, но которых нет в списке, и стадии extra перед деплоем.
+func ensureStages(pipeline string, extra ...string) string {
+	lines := strings.Split(pipeline, "\n")
+	start := -1
+	for i, l := range lines {
+		if strings.TrimRight(l, " ") == "stages:" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return pipeline
+	}
+	end := start + 1
+	var stages []string
+	for ; end < len(lines); end++ {
+		item := strings.TrimSpace(lines[end])
+		if !strings.HasPrefix(item, "- ") {
+			break
+		}
+		if s := strings.TrimSpace(strings.TrimPrefix(item, "- ")); s != "deploy" && s != "deploy_staging" && s != "deploy_production" {
+			stages = append(stages, s)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, s := range stages {
+		known[s] = true
+	}
+	used := regexp.MustCompile(`(?m)^\s+stage:\s*([A-Za-z0-9_-]+)\s*$`)
+	for _, m := range used.FindAllStringSubmatch(pipeline, -1) {
+		if s := m[1]; !known[s] && !strings.HasPrefix(s, "deploy") {
+			known[s] = true
+			stages = append(stages, s)
+		}
+	}
+	for _, s := range extra {
+		if !known[s] {
+			stages = append(stages, s)
+		}
+	}
+	stages = append(stages, "deploy_staging", "deploy_production")
+
+	block := make([]string, 0, len(stages))
+	for _, s := range stages {
+		block = append(block, "  - "+s)
+	}
+	out := append(append(append([]string{}, lines[:start+1]...), block...), lines[end:]...)
+	return strings.Join(out, "\n")
+}
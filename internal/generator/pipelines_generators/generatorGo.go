@@ -7,14 +7,14 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
 )
 
 // GenerateGoPipeline рендерит GitLab CI из go-шаблона, создаёт 'gentmp',
 // сохраняет 'gentmp/.gitlab-ci.yml', печатает его в консоль и добавляет docker-джобу,
 // используя сгенерированный или существующий Dockerfile.
-func GenerateGoPipeline(repoName string, repoRoot string, analysis *analyzer.ProjectAnalysisResult) error {
+func GenerateGoPipeline(repoName string, repoRoot string, analysis *dto.ProjectDTO) error {
 	// 0) Сначала сгенерируем/скопируем Dockerfile в gentmp
 	dockerfilePath, err := dockerfiles_generators.GenerateGoDockerfile(repoRoot, analysis)
 	if err != nil {
@@ -41,13 +41,9 @@ func GenerateGoPipeline(repoName string, repoRoot string, analysis *analyzer.Pro
 
 	if analysis != nil && len(analysis.Modules) > 0 {
 		m := analysis.Modules[0]
-		// Версия: сначала LanguageVersion, если пусто - берём FrameworkVersion (в текущем анализаторе туда ложится версия Go)
-		versionCandidate := strings.TrimSpace(m.LanguageVersion)
-		if versionCandidate == "" {
-			versionCandidate = strings.TrimSpace(m.FrameworkVersion)
-		}
-		if versionCandidate != "" {
-			goVersion = strings.TrimPrefix(versionCandidate, "go ")
+		// Версия: линия X.Y из анализа — тег golang:<X.Y> есть всегда, в отличие от патч-версий
+		if v := strings.TrimSpace(m.LanguageVersion); v != "" {
+			goVersion = goMinorVersionLocal(strings.TrimPrefix(v, "go "))
 		}
 		// Имя бинарника: последний сегмент из module name (m.Name) либо имя репозитория.
 		rawName := strings.TrimSpace(m.Name)
@@ -64,14 +60,33 @@ func GenerateGoPipeline(repoName string, repoRoot string, analysis *analyzer.Pro
 		binaryName = sanitizeBinaryName(repoName)
 	}
 
+	// Бинарники "имя:пакет" — по одному на каждый package main
+	buildTargets := binaryName + ":."
+	if analysis != nil && len(analysis.Modules) > 0 && len(analysis.Modules[0].BuildTargets) > 0 {
+		pairs := make([]string, 0, len(analysis.Modules[0].BuildTargets))
+		for _, t := range analysis.Modules[0].BuildTargets {
+			pairs = append(pairs, sanitizeBinaryName(t.Name)+":"+t.Path)
+		}
+		buildTargets = strings.Join(pairs, " ")
+	}
+
 	// 4) Подстановка плейсхолдеров ${VAR} и ${VAR:-default}
 	rendered := renderWithDefaults(tpl, map[string]string{
 		"GOLANG_VERSION": goVersion,
 		"BINARY_NAME":    binaryName,
+		"BUILD_TARGETS":  buildTargets,
 	})
 
-	// 5) Добавляем docker-джобу, если её нет
-	rendered = appendGoDockerJob(rendered, dockerfilePath)
+	// 5) Добавляем docker-джобу, если её нет; Dockerfile модуля из репозитория — со своим контекстом
+	buildArgs := "-f " + dockerfilePath + " ."
+	if m := analysis.ModuleFor(dto.LangGo); m != nil && m.Docker.DockerfilePath != "" {
+		buildArgs = m.Docker.BuildArgs()
+	}
+	rendered = appendGoDockerJob(rendered, buildArgs)
+
+	if err := checkRegistryImage(rendered); err != nil {
+		return err
+	}
 
 	// 6) Сохранение в gentmp/.gitlab-ci.yml
 	outPath := filepath.Join(tmpDir, ".gitlab-ci.yml")
@@ -129,10 +144,51 @@ func renderWithDefaults(tpl string, vars map[string]string) string {
 	return tpl
 }
 
-// appendGoDockerJob добавляет docker stage+job, если их нет, используя указанный Dockerfile
-func appendGoDockerJob(yaml string, dockerfilePath string) string {
+// appendGoDockerJob добавляет docker stage+job, если их нет; buildArgs — "-f <Dockerfile> <контекст>"
+func appendGoDockerJob(yaml string, buildArgs string) string {
 	if !strings.Contains(yaml, "stage: docker") && !strings.Contains(yaml, "- docker") {
-		yaml += "\n\ndocker_build_push:\n  stage: docker\n  image: docker:24.0.7\n  services:\n    - name: docker:24.0.7-dind\n      command: [\"--tls=false\"]\n  variables:\n    DOCKER_DRIVER: overlay2\n  script:\n    - IMAGE=\"${CI_REGISTRY_IMAGE:-}\"\n    - TAG=\"${CI_COMMIT_SHORT_SHA:-local}\"\n    - if [ -z \"$IMAGE\" ]; then echo \"No image configured, set REGISTRY\"; exit 1; fi\n    - docker build -t \"$IMAGE:$TAG\" -f " + dockerfilePath + " .\n    - docker push \"$IMAGE:$TAG\"\n    - if [ \"$CI_COMMIT_BRANCH\" = \"main\" ] || [ \"$CI_COMMIT_BRANCH\" = \"master\" ]; then docker tag \"$IMAGE:$TAG\" \"$IMAGE:latest\"; docker push \"$IMAGE:latest\"; fi\n  only:\n    - branches\n"
+		yaml += "\n\ndocker_build_push:\n  stage: docker\n  image: docker:24.0.7\n  services:\n    - name: docker:24.0.7-dind\n      command: [\"--tls=false\"]\n  variables:\n    DOCKER_DRIVER: overlay2\n  script:\n    - IMAGE=\"${CI_REGISTRY_IMAGE:-}\"\n    - TAG=\"${CI_COMMIT_SHORT_SHA:-local}\"\n    - if [ -z \"$IMAGE\" ]; then echo \"No image configured, set REGISTRY\"; exit 1; fi\n    - docker build -t \"$IMAGE:$TAG\" " + buildArgs + "\n    - docker push \"$IMAGE:$TAG\"\n    - if [ \"$CI_COMMIT_BRANCH\" = \"main\" ] || [ \"$CI_COMMIT_BRANCH\" = \"master\" ]; then docker tag \"$IMAGE:$TAG\" \"$IMAGE:latest\"; docker push \"$IMAGE:latest\"; fi\n  only:\n    - branches\n"
 	}
 	return yaml
 }
+
+// useDockerBuildArgs направляет docker build пайплайна на Dockerfile модуля из репозитория
+// и его контекст вместо сгенерированного gentmp/Dockerfile и корня репозитория.
+func useDockerBuildArgs(yaml string, analysis *dto.ProjectDTO, lang string) string {
+	m := analysis.ModuleFor(lang)
+	if m == nil || m.Docker.DockerfilePath == "" {
+		return yaml
+	}
+	return strings.ReplaceAll(yaml, "-f "+dto.GeneratedDockerfile+" .", m.Docker.BuildArgs())
+}
+
+// emptyImageRe — присваивание пустого образа в docker-джобе: IMAGE="" или IMAGE=""/<модуль>.
+var emptyImageRe = regexp.MustCompile(`\bIMAGE=(""|'')`)
+
+// checkRegistryImage проверяет, что docker-джоба публикует образ $CI_REGISTRY_IMAGE, который выкатывают
+// джобы деплоя: ${VAR:-...} рендерер раскрывает при генерации, и пустой IMAGE молча роняет джобу в CI.
+func checkRegistryImage(pipeline string) error {
+	if !strings.Contains(pipeline, "docker push") {
+		return nil
+	}
+	if emptyImageRe.MatchString(pipeline) || !strings.Contains(pipeline, "CI_REGISTRY_IMAGE") {
+		return fmt.Errorf("docker job in the rendered pipeline has an empty image reference (want $CI_REGISTRY_IMAGE)")
+	}
+	return nil
+}
+
+// goMinorVersionLocal: "1.22.3" -> "1.22", "1.21rc2" -> "1.21".
+func goMinorVersionLocal(v string) string {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(v), "go"), ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	minor := parts[1]
+	for i := 0; i < len(minor); i++ {
+		if minor[i] < '0' || minor[i] > '9' {
+			minor = minor[:i]
+			break
+		}
+	}
+	return parts[0] + "." + minor
+}
@@ -7,12 +7,12 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
 )
 
 // GenerateJavaPipeline генерирует GitLab CI для Java (Maven/Gradle) + docker job.
-func GenerateJavaPipeline(repoName string, repoRoot string, analysis *analyzer.ProjectAnalysisResult) error {
+func GenerateJavaPipeline(repoName string, repoRoot string, analysis *dto.ProjectDTO) error {
 	_, err := dockerfiles_generators.GenerateJavaDockerfile(repoRoot, analysis)
 	if err != nil {
 		return fmt.Errorf("generate java dockerfile: %w", err)
@@ -27,10 +27,20 @@ func GenerateJavaPipeline(repoName string, repoRoot string, analysis *analyzer.P
 	buildTool := "maven"
 	javaVersion := "17"
 	appName := repoName
+	jarPath := ""
+	projectArgs, moduleArgs, surefire := "", "", ""
+	mavenImage, gradleImage, gradleTasks := "", "", ""
 	if analysis != nil {
 		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguageJava {
-				bt := strings.ToLower(string(m.BuildTool))
+			if m.Language == dto.LangJava {
+				if m.BuildTool == dto.BuildMaven {
+					mavenImage = m.BuilderImage
+					jarPath, projectArgs, moduleArgs, surefire = mavenReactorArgs(m, analysis)
+				} else {
+					gradleImage = m.BuilderImage
+					jarPath, projectArgs, gradleTasks, surefire = gradleBuildArgs(m, analysis)
+				}
+				bt := strings.ToLower(m.BuildTool)
 				if strings.Contains(bt, "gradle") {
 					buildTool = "gradle"
 				}
@@ -59,13 +69,25 @@ func GenerateJavaPipeline(repoName string, repoRoot string, analysis *analyzer.P
 	yaml := string(data)
 
 	yaml = renderWithDefaultsJava(yaml, map[string]string{
-		"JAVA_VERSION": javaVersion,
-		"APP_NAME":     sanitizeNameJava(appName),
-		"JAR_PATH":     chooseJarPath(buildTool),
+		"JAVA_VERSION":        javaVersion,
+		"APP_NAME":            sanitizeNameJava(appName),
+		"JAR_PATH":            firstNonEmpty(jarPath, chooseJarPath(buildTool)),
+		"MAVEN_PROJECT_ARGS":  projectArgs,
+		"MAVEN_MODULE_ARGS":   moduleArgs,
+		"MAVEN_IMAGE":         mavenImage,
+		"SUREFIRE_REPORTS":    surefire,
+		"TEST_RESULTS":        surefire,
+		"GRADLE_IMAGE":        gradleImage,
+		"GRADLE_PROJECT_ARGS": projectArgs,
+		"GRADLE_TASKS":        gradleTasks,
 	})
 	if !strings.Contains(yaml, "gentmp/Dockerfile") {
 		yaml = strings.ReplaceAll(yaml, "Dockerfile", "gentmp/Dockerfile")
 	}
+	yaml = useDockerBuildArgs(yaml, analysis, dto.LangJava)
+	if err := checkRegistryImage(yaml); err != nil {
+		return err
+	}
 
 	outPath := filepath.Join(tmpDir, ".gitlab-ci.yml")
 	if err := os.WriteFile(outPath, []byte(yaml), 0o644); err != nil {
@@ -78,6 +100,82 @@ func GenerateJavaPipeline(repoName string, repoRoot string, analysis *analyzer.P
 	return nil
 }
 
+// mavenReactorArgs собирает аргументы mvn для reactor-а: -f, если корневой pom.xml не в корне
+// репозитория, и -pl <модули> -am по всем исполняемым модулям того же reactor-а.
+func mavenReactorArgs(module *dto.AnalyzeDTO, analysis *dto.ProjectDTO) (jarPath, projectArgs, moduleArgs, surefire string) {
+	root := strings.Trim(module.BuildRoot, "/")
+	prefix := ""
+	if root != "" && root != "." {
+		projectArgs = "-f " + root + "/pom.xml"
+		prefix = root + "/"
+		jarPath = prefix + "target/*.jar"
+		surefire = prefix + "target/surefire-reports/"
+	}
+	if module.ReactorModule == "" {
+		return jarPath, projectArgs, moduleArgs, surefire
+	}
+
+	var selected []string
+	for _, m := range analysis.Modules {
+		if m.BuildTool == dto.BuildMaven && m.BuildRoot == module.BuildRoot && m.ReactorModule != "" {
+			selected = append(selected, m.ReactorModule)
+		}
+	}
+	moduleArgs = "-pl " + strings.Join(selected, ",") + " -am"
+	jarPath = prefix + module.ReactorModule + "/target/*.jar"
+	if len(selected) > 1 {
+		jarPath = prefix + "**/target/*.jar"
+	}
+	surefire = prefix + "**/target/surefire-reports/"
+	return jarPath, projectArgs, moduleArgs, surefire
+}
+
+// gradleBuildArgs: -p <root>, если сборка не в корне, и задачи всех исполняемых подпроектов той же сборки.
+func gradleBuildArgs(module *dto.AnalyzeDTO, analysis *dto.ProjectDTO) (jarPath, projectArgs, tasks, testResults string) {
+	root := strings.Trim(module.BuildRoot, "/")
+	prefix := ""
+	if root != "" && root != "." {
+		projectArgs = "-p " + root
+		prefix = root + "/"
+		testResults = prefix + "build/test-results/test/"
+	}
+	jarPath = strings.TrimPrefix(module.ArtifactPath, "./")
+	if module.ReactorModule == "" {
+		if module.BuildTask != "" && module.BuildTask != "build" {
+			tasks = module.BuildTask
+		}
+		return jarPath, projectArgs, tasks, testResults
+	}
+
+	var selected []string
+	patterns := make(map[string]bool)
+	for _, m := range analysis.Modules {
+		if m.BuildTool == dto.BuildGradle && m.BuildRoot == module.BuildRoot && m.ReactorModule != "" {
+			selected = append(selected, m.BuildTask)
+			// services/api/build/libs/*.jar -> **/build/libs/*.jar: один шаблон артефактов на все подпроекты
+			rest := strings.TrimPrefix(strings.TrimPrefix(m.ArtifactPath, prefix), m.ReactorModule+"/")
+			patterns[rest] = true
+		}
+	}
+	tasks = strings.Join(selected, " ")
+	if len(selected) > 1 && len(patterns) == 1 {
+		for rest := range patterns {
+			jarPath = prefix + "**/" + rest
+		}
+	}
+	testResults = prefix + "**/build/test-results/test/"
+	return jarPath, projectArgs, tasks, testResults
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 func chooseJarPath(tool string) string {
 	if tool == "gradle" {
 		return "build/libs/*.jar"
@@ -5,9 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
 )
 
@@ -16,7 +17,7 @@ import (
 // 2) Рендер шаблона пайплайна templates/gitlab/pipelines/node.gitlab-ci.yml.tmpl
 // 3) Подстановка плейсхолдеров ${NODE_VERSION}, ${APP_NAME}, ${BUILD_DIR}
 // 4) Сохранение в gentmp/.gitlab-ci.yml и вывод
-func GenerateNodePipeline(repoName string, repoRoot string, analysis *analyzer.ProjectAnalysisResult) error {
+func GenerateNodePipeline(repoName string, repoRoot string, analysis *dto.ProjectDTO) error {
 	_, err := dockerfiles_generators.GenerateNodeDockerfile(repoRoot, analysis)
 	if err != nil {
 		return fmt.Errorf("generate node dockerfile: %w", err)
@@ -40,12 +41,17 @@ func GenerateNodePipeline(repoName string, repoRoot string, analysis *analyzer.P
 	nodeVersion := "20"
 	appName := repoName
 	buildDir := "dist"
+	var primary *dto.AnalyzeDTO
 	if analysis != nil && len(analysis.Modules) > 0 {
 		for _, m := range analysis.Modules {
-			if m.Language == analyzer.LanguageJavaScript || m.Language == analyzer.LanguageTypeScript {
+			if m.CanonicalLanguage() == dto.LangNode {
 				if v := strings.TrimSpace(m.LanguageVersion); v != "" {
 					nodeVersion = v
 				}
+				if a := strings.TrimSpace(m.ArtifactPath); a != "" {
+					buildDir = a
+				}
+				primary = m
 				break
 			}
 		}
@@ -53,17 +59,28 @@ func GenerateNodePipeline(repoName string, repoRoot string, analysis *analyzer.P
 	nodeVersion = normalizeNodeVersionLocal(nodeVersion)
 	appName = sanitizeName(appName)
 
-	// 4) Подстановка
-	yaml = renderWithDefaultsNode(yaml, map[string]string{
-		"NODE_VERSION": nodeVersion,
-		"APP_NAME":     appName,
-		"BUILD_DIR":    buildDir,
-	})
+	// Monorepo (npm/yarn/pnpm workspaces, Turborepo, Nx) — отдельный шаблон с графом пакетов
+	if graph := nodeWorkspaceGraph(analysis, primary); graph != nil {
+		yaml, err = renderNodeWorkspacePipeline(repoRoot, appName, nodeVersion, analysis, primary, graph)
+		if err != nil {
+			return err
+		}
+	} else {
+		yaml = renderWithDefaultsNode(yaml, map[string]string{
+			"NODE_VERSION": nodeVersion,
+			"APP_NAME":     appName,
+			"BUILD_DIR":    buildDir,
+		})
+		// Убедиться, что docker job использует наш Dockerfile путь (gentmp/Dockerfile)
+		if !strings.Contains(yaml, "gentmp/Dockerfile") {
+			// Простая замена, если вдруг шаблон другой.
+			yaml = strings.ReplaceAll(yaml, "Dockerfile", "gentmp/Dockerfile")
+		}
+		yaml = useDockerBuildArgs(yaml, analysis, dto.LangNode)
+	}
 
-	// 5) Убедиться, что docker job использует наш Dockerfile путь (gentmp/Dockerfile)
-	if !strings.Contains(yaml, "gentmp/Dockerfile") {
-		// Простая замена, если вдруг шаблон другой.
-		yaml = strings.ReplaceAll(yaml, "Dockerfile", "gentmp/Dockerfile")
+	if err := checkRegistryImage(yaml); err != nil {
+		return err
 	}
 
 	// 6) Сохранение
@@ -125,18 +142,16 @@ func normalizeNodeVersionLocal(raw string) string {
 	if raw == "" {
 		return "20"
 	}
-	re := regexp.MustCompile(`\d{1,2}`)
+	re := regexp.MustCompile(`\d+`)
 	nums := re.FindAllString(raw, -1)
 	if len(nums) == 0 {
 		return "20"
 	}
-	// берем минимальную >= 14
-	min := "20"
+	// берем первую мажорную >= 14 (сравнение числовое: "9" < "14")
 	for _, n := range nums {
-		if n >= "14" {
-			min = n
-			break
+		if major, err := strconv.Atoi(n); err == nil && major >= 14 {
+			return n
 		}
 	}
-	return min
+	return "20"
 }
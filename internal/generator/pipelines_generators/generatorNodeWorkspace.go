@@ -0,0 +1,186 @@
+package pipelines_generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
+)
+
+// nodeWorkspacePackage — пакет workspace-а в шаблоне пайплайна.
+type nodeWorkspacePackage struct {
+	Name         string
+	Slug         string
+	Path         string // относительно корня репозитория
+	Output       string // каталог сборки относительно пакета (dist, build, out, ...)
+	Build        bool
+	Test         bool
+	BuildCommand string
+	TestCommand  string
+	Changes      []string // пути для rules:changes — сам пакет, его workspace-зависимости, lock-файл
+	Dockerfile   string
+	Context      string // контекст docker build; "" — корень workspace-а
+}
+
+type nodeWorkspaceTplData struct {
+	NodeVersion    string
+	AppName        string
+	PackageManager string
+	Orchestrator   string
+	Root           string
+	RootPrefix     string
+	InstallCommand string
+	Lockfile       string
+	Packages       []nodeWorkspacePackage
+	Deployables    []nodeWorkspacePackage
+}
+
+// nodeWorkspaceGraph возвращает граф workspace-а, к которому относится модуль.
+func nodeWorkspaceGraph(analysis *dto.ProjectDTO, m *dto.AnalyzeDTO) *dto.BuildGraph {
+	if analysis == nil || m == nil || m.BuildRoot == "" {
+		return nil
+	}
+	for i := range analysis.BuildGraphs {
+		g := &analysis.BuildGraphs[i]
+		if g.Root == m.BuildRoot && g.BuildTool == m.BuildTool && g.BuildTool != dto.BuildMaven && g.BuildTool != dto.BuildGradle {
+			return g
+		}
+	}
+	return nil
+}
+
+// renderNodeWorkspacePipeline рендерит templates/gitlab/pipelines/node_workspaces.gitlab-ci.yml.tmpl:
+// turbo run --filter / nx affected, либо отдельные build/test jobs на каждый пакет с rules:changes.
+func renderNodeWorkspacePipeline(repoRoot, appName, nodeVersion string, analysis *dto.ProjectDTO, primary *dto.AnalyzeDTO, graph *dto.BuildGraph) (string, error) {
+	tplPath := filepath.Join("templates", "gitlab", "pipelines", "node_workspaces.gitlab-ci.yml.tmpl")
+	raw, err := os.ReadFile(tplPath)
+	if err != nil {
+		return "", fmt.Errorf("read node workspaces pipeline template: %w", err)
+	}
+
+	prefix := ""
+	if graph.Root != "." {
+		prefix = graph.Root + "/"
+	}
+	data := nodeWorkspaceTplData{
+		NodeVersion:    nodeVersion,
+		AppName:        appName,
+		PackageManager: graph.BuildTool,
+		Orchestrator:   graph.Orchestrator,
+		Root:           graph.Root,
+		RootPrefix:     prefix,
+		Lockfile:       prefix + "package.json",
+	}
+	switch graph.BuildTool {
+	case dto.PmPnpm:
+		data.InstallCommand = "corepack enable && pnpm config set store-dir .pnpm-store && pnpm install --frozen-lockfile"
+		if fileExistsLocal(filepath.Join(repoRoot, prefix+"pnpm-lock.yaml")) {
+			data.Lockfile = prefix + "pnpm-lock.yaml"
+		}
+	case dto.PmYarn:
+		data.InstallCommand = "corepack enable && yarn install --frozen-lockfile"
+		if fileExistsLocal(filepath.Join(repoRoot, prefix+"yarn.lock")) {
+			data.Lockfile = prefix + "yarn.lock"
+		}
+	default:
+		data.InstallCommand = "npm ci --cache .npm --prefer-offline"
+		if fileExistsLocal(filepath.Join(repoRoot, prefix+"package-lock.json")) {
+			data.Lockfile = prefix + "package-lock.json"
+		} else {
+			data.InstallCommand = "npm install --cache .npm --prefer-offline"
+		}
+	}
+
+	paths := make(map[string]string)
+	for _, n := range graph.Nodes {
+		paths[n.Name] = prefix + n.Path
+	}
+	outputs := make(map[string]string)
+	modules := make(map[string]*dto.AnalyzeDTO)
+	for _, m := range analysis.Modules {
+		if m.BuildRoot == graph.Root && m.BuildTool == graph.BuildTool && m.ReactorModule != "" {
+			// в артефакт идёт весь каталог сборки: .next/standalone без .next/static не запустится
+			outputs[m.ReactorModule] = strings.SplitN(m.ArtifactPath, "/", 2)[0]
+			modules[m.ReactorModule] = m
+		}
+	}
+	for _, n := range graph.Nodes {
+		pkg := nodeWorkspacePackage{
+			Name:         n.Name,
+			Slug:         sanitizeName(strings.TrimPrefix(strings.ReplaceAll(n.Name, "/", "-"), "@")),
+			Path:         prefix + n.Path,
+			Output:       firstNonEmpty(outputs[n.Path], "dist"),
+			Build:        containsScript(n.Scripts, "build"),
+			Test:         containsScript(n.Scripts, "test"),
+			BuildCommand: workspaceScriptCommand(graph.BuildTool, n.Name, "build"),
+			TestCommand:  workspaceScriptCommand(graph.BuildTool, n.Name, "test"),
+			Changes:      []string{prefix + n.Path + "/**/*"},
+		}
+		for _, dep := range n.DependsOn {
+			pkg.Changes = append(pkg.Changes, paths[dep]+"/**/*")
+		}
+		pkg.Changes = append(pkg.Changes, data.Lockfile)
+		data.Packages = append(data.Packages, pkg)
+
+		if !n.Runnable {
+			continue
+		}
+		m := modules[n.Path]
+		if m != nil && m.Docker.DockerfilePath != "" {
+			// Dockerfile пакета, найденный анализатором (в т.ч. в docker/, deploy/), — со своим контекстом
+			pkg.Dockerfile, pkg.Context = m.Docker.Dockerfile(), m.Docker.Context()
+		} else if fileExistsLocal(filepath.Join(repoRoot, pkg.Path, "Dockerfile")) {
+			pkg.Dockerfile = pkg.Path + "/Dockerfile"
+		} else if primary != nil && primary.ReactorModule == n.Path {
+			// для основного пакета собран gentmp/Dockerfile
+			pkg.Dockerfile = "gentmp/Dockerfile"
+		} else if m != nil {
+			// остальным пакетам с шаблоном фреймворка — gentmp/<slug>/Dockerfile
+			pkg.Dockerfile, err = dockerfiles_generators.GenerateNodeWorkspaceDockerfile(repoRoot, analysis, m, pkg.Slug)
+			if err != nil {
+				return "", fmt.Errorf("generate dockerfile for %s: %w", n.Name, err)
+			}
+		}
+		data.Deployables = append(data.Deployables, pkg)
+	}
+
+	tpl, err := template.New("node-workspaces-ci").Option("missingkey=zero").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse node workspaces pipeline template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render node workspaces pipeline: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// workspaceScriptCommand — запуск скрипта одного пакета без оркестратора.
+func workspaceScriptCommand(pm, name, script string) string {
+	switch pm {
+	case dto.PmPnpm:
+		return fmt.Sprintf("pnpm --filter %s run %s", name, script)
+	case dto.PmYarn:
+		return fmt.Sprintf("yarn workspace %s run %s", name, script)
+	}
+	return fmt.Sprintf("npm run %s -w %s", script, name)
+}
+
+func containsScript(scripts []string, name string) bool {
+	for _, s := range scripts {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExistsLocal(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
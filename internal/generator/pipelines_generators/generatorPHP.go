@@ -0,0 +1,69 @@
+package pipelines_generators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
+)
+
+// GeneratePHPPipeline генерирует GitLab CI для PHP (Composer) + docker job.
+// Шаблон: templates/gitlab/pipelines/php.gitlab-ci.yml.tmpl (install -> phpunit -> docker -> deploy).
+func GeneratePHPPipeline(repoName string, repoRoot string, analysis *dto.ProjectDTO) error {
+	if _, err := dockerfiles_generators.GeneratePHPDockerfile(repoRoot, analysis); err != nil {
+		return fmt.Errorf("generate php dockerfile: %w", err)
+	}
+
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("mkdir gentmp: %w", err)
+	}
+
+	phpVersion := "8.2"
+	appName := repoName
+	testCommand := "vendor/bin/phpunit"
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			if m.Language == dto.LangPHP {
+				if v := strings.TrimSpace(m.LanguageVersion); v != "" {
+					phpVersion = v
+				}
+				if n := strings.TrimSpace(m.Name); n != "" {
+					appName = n
+				}
+				if t := strings.TrimSpace(m.TestCommand); t != "" {
+					testCommand = t
+				}
+				break
+			}
+		}
+	}
+
+	tplPath := filepath.Join("templates", "gitlab", "pipelines", "php.gitlab-ci.yml.tmpl")
+	data, err := os.ReadFile(tplPath)
+	if err != nil {
+		return fmt.Errorf("read php pipeline template: %w", err)
+	}
+	yaml := renderWithDefaults(string(data), map[string]string{
+		"PHP_VERSION":  phpVersion,
+		"APP_NAME":     sanitizeName(appName),
+		"TEST_COMMAND": testCommand,
+	})
+	yaml = useDockerBuildArgs(yaml, analysis, dto.LangPHP)
+	if err := checkRegistryImage(yaml); err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(tmpDir, ".gitlab-ci.yml")
+	if err := os.WriteFile(outPath, []byte(yaml), 0o644); err != nil {
+		return fmt.Errorf("write php pipeline: %w", err)
+	}
+	fmt.Println("----- .gitlab-ci.yml (php) -----")
+	fmt.Println(yaml)
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return nil
+}
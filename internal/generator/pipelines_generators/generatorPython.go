@@ -9,7 +9,7 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
 	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
 )
 
@@ -32,7 +32,7 @@ type pythonTplData struct {
 
 // GeneratePythonPipeline рендерит GitLab CI из python-темплейта, создаёт 'gentmp',
 // генерирует/копирует Dockerfile в gentmp/Dockerfile, сохраняет 'gentmp/.gitlab-ci.yml' и печатает его.
-func GeneratePythonPipeline(analysis *analyzer.ProjectAnalysisResult) error {
+func GeneratePythonPipeline(analysis *dto.ProjectDTO) error {
 	// 0) Dockerfile
 	if _, err := dockerfiles_generators.GeneratePythonDockerfile(filepath.Join("gentmp", ".."), analysis); err != nil {
 		// repoRoot нам не передают; генератор Python Dockerfile сам копирует/рендерит в gentmp/Dockerfile из шаблона
@@ -58,10 +58,8 @@ func GeneratePythonPipeline(analysis *analyzer.ProjectAnalysisResult) error {
 	if m := firstPythonModule(analysis); m != nil {
 		if v := strings.TrimSpace(m.LanguageVersion); v != "" {
 			report.LanguageVersion = v
-		} else if v := strings.TrimSpace(m.FrameworkVersion); v != "" {
-			report.LanguageVersion = v
 		}
-		if p := strings.TrimSpace(m.AppPort); p != "" {
+		if p := m.Port(); p != "" {
 			report.AppPort = p
 		}
 	}
@@ -81,9 +79,10 @@ func GeneratePythonPipeline(analysis *analyzer.ProjectAnalysisResult) error {
 		return fmt.Errorf("execute template: %w", err)
 	}
 
-	// 6) Гарантировать, что docker job использует gentmp/Dockerfile
+	// 6) Гарантировать, что docker job использует gentmp/Dockerfile или Dockerfile модуля из репозитория
 	yaml := buf.String()
 	yaml = strings.ReplaceAll(yaml, "-f Dockerfile", "-f gentmp/Dockerfile")
+	yaml = useDockerBuildArgs(yaml, analysis, dto.LangPython)
 
 	// 7) Сохранение и вывод
 	outPath := filepath.Join(tmpDir, ".gitlab-ci.yml")
@@ -98,12 +97,12 @@ func GeneratePythonPipeline(analysis *analyzer.ProjectAnalysisResult) error {
 	return nil
 }
 
-func firstPythonModule(analysis *analyzer.ProjectAnalysisResult) *analyzer.ProjectModule {
+func firstPythonModule(analysis *dto.ProjectDTO) *dto.AnalyzeDTO {
 	if analysis == nil {
 		return nil
 	}
 	for _, m := range analysis.Modules {
-		if m.Language == analyzer.LanguagePython {
+		if m.Language == dto.LangPython {
 			return m
 		}
 	}
@@ -0,0 +1,69 @@
+package pipelines_generators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/dto"
+	"github.com/Dancoi/gogen-self-deploy/internal/generator/dockerfiles_generators"
+)
+
+// GenerateRubyPipeline генерирует GitLab CI для Ruby (Bundler) + docker job.
+// Шаблон: templates/gitlab/pipelines/ruby.gitlab-ci.yml.tmpl (install -> rspec -> docker -> deploy).
+func GenerateRubyPipeline(repoName string, repoRoot string, analysis *dto.ProjectDTO) error {
+	if _, err := dockerfiles_generators.GenerateRubyDockerfile(repoRoot, analysis); err != nil {
+		return fmt.Errorf("generate ruby dockerfile: %w", err)
+	}
+
+	tmpDir := "gentmp"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("mkdir gentmp: %w", err)
+	}
+
+	rubyVersion := "3.3"
+	appName := repoName
+	testCommand := "bundle exec rspec"
+	if analysis != nil {
+		for _, m := range analysis.Modules {
+			if m.Language == dto.LangRuby {
+				if v := strings.TrimSpace(m.LanguageVersion); v != "" {
+					rubyVersion = v
+				}
+				if n := strings.TrimSpace(m.Name); n != "" {
+					appName = n
+				}
+				if t := strings.TrimSpace(m.TestCommand); t != "" {
+					testCommand = t
+				}
+				break
+			}
+		}
+	}
+
+	tplPath := filepath.Join("templates", "gitlab", "pipelines", "ruby.gitlab-ci.yml.tmpl")
+	data, err := os.ReadFile(tplPath)
+	if err != nil {
+		return fmt.Errorf("read ruby pipeline template: %w", err)
+	}
+	yaml := renderWithDefaults(string(data), map[string]string{
+		"RUBY_VERSION": rubyVersion,
+		"APP_NAME":     sanitizeName(appName),
+		"TEST_COMMAND": testCommand,
+	})
+	yaml = useDockerBuildArgs(yaml, analysis, dto.LangRuby)
+	if err := checkRegistryImage(yaml); err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(tmpDir, ".gitlab-ci.yml")
+	if err := os.WriteFile(outPath, []byte(yaml), 0o644); err != nil {
+		return fmt.Errorf("write ruby pipeline: %w", err)
+	}
+	fmt.Println("----- .gitlab-ci.yml (ruby) -----")
+	fmt.Println(yaml)
+	fmt.Println("----- end -----")
+	fmt.Println("Saved to:", outPath)
+	return nil
+}
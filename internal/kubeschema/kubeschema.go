@@ -0,0 +1,331 @@
+// Package kubeschema — офлайн-проверка манифестов Kubernetes по OpenAPI-определениям (swagger.json).
+// Схемы лежат рядом с шаблонами в templates/schemas/k8s/<версия>.json: поставляется срез v1.30
+// с генерируемыми видами ресурсов, его можно заменить полным swagger.json нужной версии кластера.
+package kubeschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultVersion — версия схем, по которой проверяются сгенерированные манифесты.
+const DefaultVersion = "v1.30"
+
+// DefaultDir — каталог схем, поставляемый вместе с шаблонами.
+var DefaultDir = filepath.Join("templates", "schemas", "k8s")
+
+const quantityDef = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+
+// Schema — определения одной версии Kubernetes API и индекс видов ресурсов.
+type Schema struct {
+	Version string
+	defs    map[string]*node
+	kinds   map[string]string // "apps/v1 Deployment" -> имя определения
+}
+
+type node struct {
+	Type                 string           `json:"type"`
+	Format               string           `json:"format"`
+	Pattern              string           `json:"pattern"`
+	Enum                 []any            `json:"enum"`
+	Ref                  string           `json:"$ref"`
+	Required             []string         `json:"required"`
+	Properties           map[string]*node `json:"properties"`
+	AdditionalProperties *node            `json:"additionalProperties"`
+	Items                *node            `json:"items"`
+	PreserveUnknown      bool             `json:"x-kubernetes-preserve-unknown-fields"`
+	GroupVersionKind     []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind"`
+
+	pattern *regexp.Regexp
+}
+
+// Load читает схемы версии version (например, "v1.30") из DefaultDir.
+func Load(version string) (*Schema, error) {
+	return LoadFile(filepath.Join(DefaultDir, version+".json"))
+}
+
+func LoadFile(path string) (*Schema, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read k8s schema: %w", err)
+	}
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Definitions map[string]*node `json:"definitions"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse k8s schema %s: %w", path, err)
+	}
+	if len(doc.Definitions) == 0 {
+		return nil, fmt.Errorf("k8s schema %s: no definitions", path)
+	}
+	s := &Schema{Version: doc.Info.Version, defs: doc.Definitions, kinds: make(map[string]string)}
+	for name, def := range doc.Definitions {
+		for _, gvk := range def.GroupVersionKind {
+			s.kinds[apiVersion(gvk.Group, gvk.Version)+" "+gvk.Kind] = name
+		}
+		if err := compilePatterns(def); err != nil {
+			return nil, fmt.Errorf("k8s schema %s: %s: %w", path, name, err)
+		}
+	}
+	return s, nil
+}
+
+func compilePatterns(n *node) error {
+	if n == nil {
+		return nil
+	}
+	if n.Pattern != "" && n.pattern == nil {
+		re, err := regexp.Compile(n.Pattern)
+		if err != nil {
+			return err
+		}
+		n.pattern = re
+	}
+	for _, p := range n.Properties {
+		if err := compilePatterns(p); err != nil {
+			return err
+		}
+	}
+	if err := compilePatterns(n.AdditionalProperties); err != nil {
+		return err
+	}
+	return compilePatterns(n.Items)
+}
+
+// Validate проверяет манифест (один или несколько YAML-документов через ---): известный
+// apiVersion/kind, типы и перечисления, обязательные поля и отсутствие неизвестных полей,
+// как при kubectl apply --validate=strict. Возвращает все найденные ошибки разом или nil.
+func (s *Schema) Validate(manifest []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(manifest))
+	var errs []error
+	for i := 0; ; i++ {
+		var obj any
+		if err := dec.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if obj == nil {
+			continue
+		}
+		errs = append(errs, s.validateObject(obj)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Schema) validateObject(obj any) []error {
+	m, ok := obj.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("manifest is not an object")}
+	}
+	av, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+	name := kind
+	if meta, ok := m["metadata"].(map[string]any); ok {
+		if n, ok := meta["name"].(string); ok {
+			name = kind + "/" + n
+		}
+	}
+	if av == "" || kind == "" {
+		return []error{fmt.Errorf("%s: apiVersion and kind are required", name)}
+	}
+	def, ok := s.kinds[av+" "+kind]
+	if !ok {
+		return []error{fmt.Errorf("%s: no schema for %s %s in Kubernetes %s", name, av, kind, s.Version)}
+	}
+	v := validator{schema: s}
+	v.check(s.defs[def], def, obj, "")
+	for i, err := range v.errs {
+		v.errs[i] = fmt.Errorf("%s: %w", name, err)
+	}
+	return v.errs
+}
+
+type validator struct {
+	schema *Schema
+	errs   []error
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	if path == "" {
+		path = "."
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) check(n *node, defName string, val any, path string) {
+	if n == nil {
+		return
+	}
+	if n.Ref != "" {
+		name := strings.TrimPrefix(n.Ref, "#/definitions/")
+		def, ok := v.schema.defs[name]
+		if !ok {
+			v.fail(path, "unresolved schema reference %s", n.Ref)
+			return
+		}
+		v.check(def, name, val, path)
+		return
+	}
+	if val == nil {
+		// null в манифесте равен отсутствию поля
+		return
+	}
+
+	switch n.Type {
+	case "object":
+		obj, ok := val.(map[string]any)
+		if !ok {
+			v.fail(path, "expected object, got %s", typeName(val))
+			return
+		}
+		v.checkObject(n, obj, path)
+	case "array":
+		arr, ok := val.([]any)
+		if !ok {
+			v.fail(path, "expected array, got %s", typeName(val))
+			return
+		}
+		for i, item := range arr {
+			v.check(n.Items, "", item, path+"["+strconv.Itoa(i)+"]")
+		}
+	case "string":
+		str, ok := val.(string)
+		switch {
+		case !ok && n.Format == "int-or-string" && isInteger(val):
+			return
+		case !ok && defName == quantityDef && isNumber(val):
+			return
+		case !ok:
+			v.fail(path, "expected string, got %s", typeName(val))
+			return
+		}
+		if n.pattern != nil && !n.pattern.MatchString(str) {
+			v.fail(path, "%q does not match %s", str, n.Pattern)
+		}
+		v.checkEnum(n, str, path)
+	case "integer":
+		if !isInteger(val) {
+			v.fail(path, "expected integer, got %s", typeName(val))
+		}
+	case "number":
+		if !isNumber(val) {
+			v.fail(path, "expected number, got %s", typeName(val))
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			v.fail(path, "expected boolean, got %s", typeName(val))
+		}
+	}
+}
+
+func (v *validator) checkObject(n *node, obj map[string]any, path string) {
+	for _, req := range n.Required {
+		if _, ok := obj[req]; !ok {
+			v.fail(path, "missing required field %q", req)
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		child := joinPath(path, k)
+		if p, ok := n.Properties[k]; ok {
+			v.check(p, "", obj[k], child)
+			continue
+		}
+		switch {
+		case n.AdditionalProperties != nil:
+			v.check(n.AdditionalProperties, "", obj[k], child)
+		case n.PreserveUnknown || len(n.Properties) == 0:
+			// произвольное содержимое (status, ownerReferences)
+		default:
+			v.fail(child, "unknown field")
+		}
+	}
+}
+
+func (v *validator) checkEnum(n *node, val string, path string) {
+	if len(n.Enum) == 0 {
+		return
+	}
+	allowed := make([]string, 0, len(n.Enum))
+	for _, e := range n.Enum {
+		if s, ok := e.(string); ok {
+			if s == val {
+				return
+			}
+			allowed = append(allowed, s)
+		}
+	}
+	v.fail(path, "%q is not one of %s", val, strings.Join(allowed, ", "))
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func apiVersion(group, version string) string {
+	if group == "" {
+		return version
+	}
+	return group + "/" + version
+}
+
+func isInteger(val any) bool {
+	switch x := val.(type) {
+	case int, int64, uint64:
+		return true
+	case float64:
+		return x == float64(int64(x))
+	}
+	return false
+}
+
+func isNumber(val any) bool {
+	switch val.(type) {
+	case int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+func typeName(val any) string {
+	switch val.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", val)
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+)
+
+// writeMarkdown — отчёт для README/MR: языки, модули, фреймворки, инфраструктура, сервисы и окружение.
+func writeMarkdown(w io.Writer, result *analyzer.ProjectAnalysisResult, repoRoot string) error {
+	var b strings.Builder
+	name := result.RepositoryName
+	if name == "" {
+		name = "repository"
+	}
+	fmt.Fprintf(&b, "# Analysis report: %s\n\n", name)
+	fmt.Fprintf(&b, "- Pipeline strategy: **%s**\n", orDash(string(result.PipelineStrategy)))
+	fmt.Fprintf(&b, "- Main framework: %s\n", frameworkLabel(result.MainFramework, result.MainFrameworkVersion))
+	fmt.Fprintf(&b, "- Schema version: %s\n", result.SchemaVersion)
+
+	b.WriteString("\n## Languages\n\n")
+	if shares := languageShares(result); len(shares) > 0 {
+		b.WriteString("| Language | Share |\n|---|---:|\n")
+		for _, s := range shares {
+			fmt.Fprintf(&b, "| %s | %.1f%% |\n", mdCell(s.Name), s.Percent)
+		}
+	} else {
+		b.WriteString("_No source files recognized._\n")
+	}
+
+	b.WriteString("\n## Modules\n\n")
+	if len(result.Modules) > 0 {
+		b.WriteString("| Module | Path | Language | Version | Build tool | Framework | Port |\n|---|---|---|---|---|---|---:|\n")
+		for _, m := range result.Modules {
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s | %s | %s |\n",
+				mdCell(m.Name), moduleDir(m, repoRoot), m.Language, orDash(m.LanguageVersion),
+				m.BuildTool, mdCell(frameworkLabel(m.Framework, m.FrameworkVersion)), orDash(m.AppPort))
+		}
+	} else {
+		b.WriteString("_No buildable modules detected._\n")
+	}
+
+	b.WriteString("\n## Infrastructure\n\n")
+	if len(result.Infrastructure) > 0 {
+		for _, item := range result.Infrastructure {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	} else {
+		b.WriteString("_None detected._\n")
+	}
+
+	if len(result.BackingServices) > 0 {
+		b.WriteString("\n## Backing services\n\n| Service | Used by |\n|---|---|\n")
+		for _, s := range result.BackingServices {
+			fmt.Fprintf(&b, "| %s | %s |\n", s.Name, mdCell(strings.Join(s.Modules, ", ")))
+		}
+	}
+
+	if len(result.EnvVars) > 0 {
+		b.WriteString("\n## Environment variables\n\n| Name | Required | Secret | Default |\n|---|---|---|---|\n")
+		for _, v := range result.EnvVars {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", v.Name, yesNo(v.Required), yesNo(v.Secret), mdCell(envDefault(v)))
+		}
+	}
+
+	if len(result.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mdCell экранирует символы, ломающие строку таблицы.
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,171 @@
+// Package report выводит результат анализа репозитория в машиночитаемом (JSON, YAML)
+// и человекочитаемом (Markdown, таблица) виде.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatMarkdown Format = "markdown"
+	FormatTable    Format = "table"
+)
+
+// Formats — поддерживаемые форматы в порядке для справки CLI.
+var Formats = []Format{FormatJSON, FormatYAML, FormatMarkdown, FormatTable}
+
+// ParseFormat принимает имя формата и алиасы (yml, md).
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "table", "text":
+		return FormatTable, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want json, yaml, markdown or table)", s)
+}
+
+// Write выводит результат анализа в формате format. repoRoot нужен, чтобы показать пути модулей
+// относительно репозитория; JSON и YAML выводятся как есть и соответствуют Schema().
+func Write(w io.Writer, result *analyzer.ProjectAnalysisResult, format Format, repoRoot string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(result)
+	case FormatYAML:
+		return writeYAML(w, result)
+	case FormatMarkdown:
+		return writeMarkdown(w, result, repoRoot)
+	case FormatTable:
+		return writeTable(w, result, repoRoot)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// WriteFile сохраняет отчёт в файл, создавая каталоги.
+func WriteFile(path string, result *analyzer.ProjectAnalysisResult, format Format, repoRoot string) error {
+	var buf bytes.Buffer
+	if err := Write(&buf, result, format, repoRoot); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("mkdir %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// writeYAML: JSON -> yaml.Node сохраняет имена полей из json-тегов и порядок полей структур.
+func writeYAML(w io.Writer, result *analyzer.ProjectAnalysisResult) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal analysis: %w", err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return fmt.Errorf("convert analysis to yaml: %w", err)
+	}
+	clearStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	return enc.Close()
+}
+
+// clearStyle убирает flow-стиль, унаследованный от JSON, чтобы YAML вышел блочным.
+func clearStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	if n.Kind == yaml.ScalarNode && n.Style&yaml.DoubleQuotedStyle != 0 {
+		n.Style &^= yaml.DoubleQuotedStyle
+	}
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
+
+// moduleDir — каталог модуля относительно репозитория ("." — корень).
+func moduleDir(m *analyzer.ProjectModule, repoRoot string) string {
+	dir := filepath.Dir(m.ModulePath)
+	if repoRoot != "" {
+		if rel, err := filepath.Rel(repoRoot, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+type languageShare struct {
+	Name    string
+	Percent float64
+}
+
+// languageShares — языки по убыванию доли.
+func languageShares(result *analyzer.ProjectAnalysisResult) []languageShare {
+	shares := make([]languageShare, 0, len(result.Languages))
+	for name, p := range result.Languages {
+		shares = append(shares, languageShare{name, p})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Percent != shares[j].Percent {
+			return shares[i].Percent > shares[j].Percent
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return shares
+}
+
+func frameworkLabel(name, version string) string {
+	if name == "" {
+		return "-"
+	}
+	if version != "" {
+		return name + " " + version
+	}
+	return name
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// envDefault: значение по умолчанию секретов в отчёт не попадает.
+func envDefault(v analyzer.EnvVar) string {
+	switch {
+	case !v.HasDefault:
+		return "-"
+	case v.Secret:
+		return "(hidden)"
+	case v.Default == "":
+		return `""`
+	}
+	return v.Default
+}
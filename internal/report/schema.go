@@ -0,0 +1,113 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+)
+
+// SchemaID — идентификатор схемы текущей мажорной версии результата анализа.
+var SchemaID = "gogen-self-deploy/analysis-result/v" + strings.SplitN(analyzer.AnalysisSchemaVersion, ".", 2)[0] + ".schema.json"
+
+// schemaEnums — допустимые значения строковых перечислений анализатора.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(analyzer.Language("")): {
+		string(analyzer.LanguageGo), string(analyzer.LanguagePython), string(analyzer.LanguageJava),
+		string(analyzer.LanguageJavaScript), string(analyzer.LanguageTypeScript), string(analyzer.LanguageKotlin),
+		string(analyzer.LanguagePHP), string(analyzer.LanguageRuby), string(analyzer.LanguageUnknown),
+	},
+	reflect.TypeOf(analyzer.BuildTool("")): {
+		string(analyzer.BuildToolMaven), string(analyzer.BuildToolGradle), string(analyzer.BuildToolNpm),
+		string(analyzer.BuildToolYarn), string(analyzer.BuildToolPnpm), string(analyzer.BuildToolPip),
+		string(analyzer.BuildToolPipenv), string(analyzer.BuildToolPoetry), string(analyzer.BuildToolGoModules),
+		string(analyzer.BuildToolComposer), string(analyzer.BuildToolBundler), string(analyzer.BuildToolUnknown),
+	},
+	reflect.TypeOf(analyzer.PipelineStrategy("")): {
+		string(analyzer.PipelineStrategyMonorepo), string(analyzer.PipelineStrategyStandalone),
+	},
+}
+
+// Schema строит JSON Schema (draft 2020-12) для ProjectAnalysisResult по json-тегам структур,
+// поэтому схема не расходится с кодом. Поля без omitempty — обязательные; nil-срезы и карты
+// сериализуются как null, поэтому для них допускается null.
+func Schema() map[string]any {
+	defs := make(map[string]any)
+	root := schemaForStruct(reflect.TypeOf(analyzer.ProjectAnalysisResult{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "ProjectAnalysisResult"
+	root["description"] = "Result of gogen-self-deploy repository analysis, schema version " + analyzer.AnalysisSchemaVersion
+	props := root["properties"].(map[string]any)
+	props["schema_version"] = map[string]any{
+		"type":    "string",
+		"pattern": `^` + strings.SplitN(analyzer.AnalysisSchemaVersion, ".", 2)[0] + `\.[0-9]+$`,
+	}
+	root["$defs"] = defs
+	return root
+}
+
+func schemaForStruct(t reflect.Type, defs map[string]any) map[string]any {
+	props := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		props[name] = schemaForType(f.Type, defs, !omitempty)
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	}
+}
+
+// schemaForType; nullable — nil-срез/карта/указатель попадёт в JSON как null.
+func schemaForType(t reflect.Type, defs map[string]any, nullable bool) map[string]any {
+	withNull := func(typ string) any {
+		if nullable {
+			return []string{typ, "null"}
+		}
+		return typ
+	}
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaForType(t.Elem(), defs, false)
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = map[string]any{} // защита от рекурсии
+			defs[t.Name()] = schemaForStruct(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": withNull("array"), "items": schemaForType(t.Elem(), defs, false)}
+	case reflect.Map:
+		return map[string]any{"type": withNull("object"), "additionalProperties": schemaForType(t.Elem(), defs, false)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
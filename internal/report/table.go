@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Dancoi/gogen-self-deploy/internal/analyzer"
+)
+
+// writeTable — краткая сводка для терминала.
+func writeTable(w io.Writer, result *analyzer.ProjectAnalysisResult, repoRoot string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Repository:\t%s\n", orDash(result.RepositoryName))
+	fmt.Fprintf(tw, "Strategy:\t%s\n", orDash(string(result.PipelineStrategy)))
+	fmt.Fprintf(tw, "Main framework:\t%s\n", frameworkLabel(result.MainFramework, result.MainFrameworkVersion))
+	var langs []string
+	for _, s := range languageShares(result) {
+		langs = append(langs, fmt.Sprintf("%s %.1f%%", s.Name, s.Percent))
+	}
+	fmt.Fprintf(tw, "Languages:\t%s\n", orDash(strings.Join(langs, ", ")))
+	fmt.Fprintf(tw, "Infrastructure:\t%s\n", orDash(strings.Join(result.Infrastructure, ", ")))
+	var services []string
+	for _, s := range result.BackingServices {
+		services = append(services, s.Name)
+	}
+	fmt.Fprintf(tw, "Services:\t%s\n", orDash(strings.Join(services, ", ")))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(result.Modules) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(tw, "MODULE\tPATH\tLANGUAGE\tVERSION\tBUILD TOOL\tFRAMEWORK\tPORT")
+		for _, m := range result.Modules {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", m.Name, moduleDir(m, repoRoot), m.Language,
+				orDash(m.LanguageVersion), m.BuildTool, frameworkLabel(m.Framework, m.FrameworkVersion), orDash(m.AppPort))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(result.Warnings) > 0 {
+		fmt.Fprintln(w)
+		for _, warning := range result.Warnings {
+			fmt.Fprintln(w, "Warning:", warning)
+		}
+	}
+	return nil
+}